@@ -1,9 +1,17 @@
 package async
 
 import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-live-view/go-live-view/dynamic"
 	lv "github.com/go-live-view/go-live-view/liveview"
+	"github.com/go-live-view/go-live-view/rend"
 )
 
+var _ lv.AsyncAssign = (*Async[any])(nil)
+
 type State int
 
 const (
@@ -12,12 +20,39 @@ const (
 	Failed
 )
 
+// Async holds a value fetched in the background. The result is set in
+// the liveview goroutine, mu guards reads from other goroutines.
 type Async[T any] struct {
+	mu    sync.RWMutex
 	value T
 	state State
 	err   error
 }
 
+// Assign fetches the value with Socket.AssignAsync under name. The
+// context is cancelled when the liveview leaves. The returned Async is
+// loading until the result arrives, then the view's HandleAsync is called
+// and the view is rendered again. s is nil during the static render, the
+// value stays loading then.
+func Assign[T any](s lv.Socket, name string, fetch func(context.Context) (T, error)) *Async[T] {
+	a := &Async[T]{
+		state: Loading,
+	}
+
+	if s == nil {
+		return a
+	}
+
+	s.AssignAsync(name, a, func(ctx context.Context) (any, error) {
+		return fetch(ctx)
+	})
+
+	return a
+}
+
+// New fetches the value in the background.
+//
+// Deprecated: use Assign, which cancels the fetch when the liveview leaves.
 func New[T any](s lv.Socket, fetch func() (T, error)) *Async[T] {
 	a := &Async[T]{
 		state: Loading,
@@ -27,28 +62,87 @@ func New[T any](s lv.Socket, fetch func() (T, error)) *Async[T] {
 		return a
 	}
 
-	go func(s lv.Socket) {
-		result, err := fetch()
-		if err != nil {
-			a.state = Failed
-			a.err = err
-		}
-		a.value = result
-		a.state = Loaded
-		s.PushSelf("async:update", nil)
-	}(s)
+	s.AssignAsync(fmt.Sprintf("async:%p", a), a, func(context.Context) (any, error) {
+		return fetch()
+	})
 
 	return a
 }
 
+// SetAsyncResult implements lv.AsyncAssign.
+func (a *Async[T]) SetAsyncResult(r lv.AsyncResult) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if r.Err != nil {
+		a.state = Failed
+		a.err = r.Err
+		return
+	}
+
+	a.value, _ = r.Value.(T)
+	a.state = Loaded
+	a.err = nil
+}
+
 func (a *Async[T]) Value() T {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
 	return a.value
 }
 
 func (a *Async[T]) State() State {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
 	return a.state
 }
 
 func (a *Async[T]) Error() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
 	return a.err
 }
+
+// Render renders the node matching the state of a. Nil callbacks render
+// nothing.
+func Render[T any](
+	a *Async[T],
+	loading func() rend.Node,
+	failed func(error) rend.Node,
+	ok func(T) rend.Node,
+) rend.Node {
+	return dynamic.GoEmbed(func() rend.Node {
+		a.mu.RLock()
+		state, value, err := a.state, a.value, a.err
+		a.mu.RUnlock()
+
+		switch {
+		case state == Loading && loading != nil:
+			return loading()
+		case state == Failed && failed != nil:
+			return failed(err)
+		case state == Loaded && ok != nil:
+			return ok(value)
+		default:
+			return nil
+		}
+	})
+}
+
+// WhenLoading renders n while a is loading.
+func WhenLoading[T any](a *Async[T], n rend.Node) rend.Node {
+	return Render(a, func() rend.Node { return n }, nil, nil)
+}
+
+// WhenFailed renders the node returned by f when a failed.
+func WhenFailed[T any](a *Async[T], f func(error) rend.Node) rend.Node {
+	return Render(a, nil, f, nil)
+}
+
+// WhenLoaded renders the node returned by f once a is loaded.
+func WhenLoaded[T any](a *Async[T], f func(T) rend.Node) rend.Node {
+	return Render(a, nil, nil, f)
+}
@@ -0,0 +1,66 @@
+package async
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-live-view/go-live-view/dynamic"
+	lv "github.com/go-live-view/go-live-view/liveview"
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	tt := []struct {
+		name     string
+		result   *lv.AsyncResult
+		state    State
+		expected string
+	}{
+		{
+			name:     "loading",
+			state:    Loading,
+			expected: "loading",
+		},
+		{
+			name:     "loaded",
+			result:   &lv.AsyncResult{Value: "john"},
+			state:    Loaded,
+			expected: "john",
+		},
+		{
+			name:     "failed",
+			result:   &lv.AsyncResult{Err: errors.New("boom")},
+			state:    Failed,
+			expected: "boom",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			a := Assign[string](nil, "user", nil)
+			if tc.result != nil {
+				a.SetAsyncResult(*tc.result)
+			}
+
+			assert.Equal(t, tc.state, a.State())
+
+			node := Render(a,
+				func() rend.Node { return dynamic.Text("loading") },
+				func(err error) rend.Node { return dynamic.Text(err.Error()) },
+				func(v string) rend.Node { return dynamic.Text(v) },
+			)
+			assert.Equal(t, tc.expected, rend.RenderString(node))
+		})
+	}
+}
+
+func TestFailureIsKept(t *testing.T) {
+	a := Assign[string](nil, "user", nil)
+
+	a.SetAsyncResult(lv.AsyncResult{Value: "john", Err: errors.New("boom")})
+
+	assert.Equal(t, Failed, a.State())
+	assert.EqualError(t, a.Error(), "boom")
+	assert.Empty(t, a.Value())
+}
@@ -1,30 +1,42 @@
 package channel
 
+import "sync"
+
 type Conn interface {
 	ReadMessage() ([]byte, error)
 	WriteMessage([]byte) error
 }
 
+// conn serializes writes since channels push from their own goroutines.
 type conn struct {
-	c Conn
+	c  Conn
+	mu sync.Mutex
 }
 
 func newConnection(c Conn) *conn {
 	return &conn{c: c}
 }
 
-func (t *conn) ReadMessage() (*Message, error) {
+// ReadMessage returns the next message and its size in bytes.
+func (t *conn) ReadMessage() (*Message, int, error) {
 	data, err := t.c.ReadMessage()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return decode(data)
+
+	msg, err := decode(data)
+	return msg, len(data), err
 }
 
-func (t *conn) WriteMessage(m *Message) error {
+// WriteMessage writes m and returns its size in bytes.
+func (t *conn) WriteMessage(m *Message) (int, error) {
 	data, err := encode(m)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return t.c.WriteMessage(data)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(data), t.c.WriteMessage(data)
 }
@@ -0,0 +1,88 @@
+package channel
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// Reasons replied to the client when a message fails. The LiveView client
+// reloads the page on ReasonUnauthorized, like on "stale", and rejoins on
+// a phx_error.
+const (
+	ReasonUnauthorized   = "unauthorized"
+	ReasonUnmatchedTopic = "unmatched topic"
+	ReasonJoinCrashed    = "join crashed"
+	ReasonInternal       = "internal error"
+)
+
+// ErrorHandler reports the errors of the messages of a connection, e.g. to
+// log them. The client only gets a reason, not the error.
+type ErrorHandler func(msg *Message, err error)
+
+// ReplyError is an error replied to the client with Response. Its Err is
+// reported, a ReplyError without one is only a reply, like a redirect.
+type ReplyError struct {
+	Response map[string]any
+	Err      error
+}
+
+func (e *ReplyError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("reply %v", e.Response)
+	}
+
+	return e.Err.Error()
+}
+
+func (e *ReplyError) Unwrap() error {
+	return e.Err
+}
+
+// Reason returns err replied to the client with reason.
+func Reason(reason string, err error) error {
+	return &ReplyError{
+		Response: map[string]any{
+			"reason": reason,
+		},
+		Err: err,
+	}
+}
+
+// PanicError is a panic recovered while handling a message.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Recover runs f, turning a panic into a *PanicError. Channels running
+// their callbacks on other goroutines use it so that a panic only crashes
+// the channel.
+func Recover(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+
+	return f()
+}
+
+// response returns the reply of err and whether to report it.
+func response(err error) (map[string]any, bool) {
+	var reply *ReplyError
+	if errors.As(err, &reply) {
+		return reply.Response, reply.Err != nil
+	}
+
+	return map[string]any{
+		"reason": ReasonInternal,
+	}, true
+}
@@ -2,24 +2,84 @@ package channel
 
 import (
 	"context"
+	"fmt"
 	"sync"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/rs/xid"
 )
 
+// HubTopic is the pubsub topic hubs use to exchange broadcasts.
+const HubTopic = "go-live-view:hub"
+
 type broadcaster interface {
 	Broadcast(msg *Message) error
+	BroadcastAll(msg *Message)
+}
+
+type hubOption func(*Hub)
+
+// subscriber identifies a joined channel on a server.
+type subscriber struct {
+	server broadcaster
+	topic  string
 }
 
 type Hub struct {
 	mu      sync.RWMutex
 	servers map[broadcaster]struct{}
+
+	// topics indexes subscribers by the topic they subscribed to and
+	// channels the topics by subscriber, for cleanup.
+	topics   map[string]map[subscriber]struct{}
+	channels map[subscriber]map[string]struct{}
+
+	nodeID string
+	pubsub PubSub
+}
+
+// envelope wraps a message published to other nodes. An empty topic
+// addresses all servers.
+type envelope struct {
+	Node    string         `json:"node"`
+	Topic   string         `json:"topic,omitempty"`
+	Message jsontext.Value `json:"message"`
+}
+
+func NewHub(opts ...hubOption) *Hub {
+	h := &Hub{
+		servers:  make(map[broadcaster]struct{}),
+		topics:   make(map[string]map[subscriber]struct{}),
+		channels: make(map[subscriber]map[string]struct{}),
+		nodeID:   xid.New().String(),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// WithPubSub distributes broadcasts to hubs on other nodes through ps.
+func WithPubSub(ps PubSub) hubOption {
+	return func(h *Hub) {
+		h.pubsub = ps
+	}
 }
 
-func NewHub() *Hub {
-	return &Hub{
-		servers: make(map[broadcaster]struct{}),
+// WithNodeID overrides the randomly generated node ID.
+func WithNodeID(id string) hubOption {
+	return func(h *Hub) {
+		h.nodeID = id
 	}
 }
 
+func (sr *Hub) NodeID() string {
+	return sr.nodeID
+}
+
 func (sr *Hub) Add(s broadcaster) {
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
@@ -32,26 +92,189 @@ func (sr *Hub) Remove(s broadcaster) {
 	defer sr.mu.Unlock()
 
 	delete(sr.servers, s)
+
+	for sub := range sr.channels {
+		if sub.server == s {
+			sr.unsubscribeAll(sub)
+		}
+	}
+}
+
+// Subscribe registers the channel joined on s under channelTopic to
+// receive messages published to topic.
+func (sr *Hub) Subscribe(topic string, s broadcaster, channelTopic string) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sub := subscriber{server: s, topic: channelTopic}
+
+	if sr.topics[topic] == nil {
+		sr.topics[topic] = make(map[subscriber]struct{})
+	}
+	sr.topics[topic][sub] = struct{}{}
+
+	if sr.channels[sub] == nil {
+		sr.channels[sub] = make(map[string]struct{})
+	}
+	sr.channels[sub][topic] = struct{}{}
+}
+
+// Unsubscribe removes a subscription made with Subscribe.
+func (sr *Hub) Unsubscribe(topic string, s broadcaster, channelTopic string) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.unsubscribe(topic, subscriber{server: s, topic: channelTopic})
+}
+
+// UnsubscribeAll removes all subscriptions of a channel.
+func (sr *Hub) UnsubscribeAll(s broadcaster, channelTopic string) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.unsubscribeAll(subscriber{server: s, topic: channelTopic})
+}
+
+func (sr *Hub) unsubscribeAll(sub subscriber) {
+	for topic := range sr.channels[sub] {
+		sr.unsubscribe(topic, sub)
+	}
+}
+
+func (sr *Hub) unsubscribe(topic string, sub subscriber) {
+	delete(sr.topics[topic], sub)
+	if len(sr.topics[topic]) == 0 {
+		delete(sr.topics, topic)
+	}
+
+	delete(sr.channels[sub], topic)
+	if len(sr.channels[sub]) == 0 {
+		delete(sr.channels, sub)
+	}
 }
 
+// WriteMessage sends msg to every joined channel of all servers on all
+// nodes. The message topic is replaced with the topic of each channel.
 func (sr *Hub) WriteMessage(msg *Message) error {
-	// send to all sockets
-	for s := range sr.servers {
-		err := s.Broadcast(msg)
-		if err != nil {
-			return err
-		}
+	sr.broadcast(msg)
+
+	return sr.publishRemote("", msg)
+}
+
+// Publish sends msg to every channel subscribed to topic on all nodes.
+// The message topic is replaced with the topic of each subscribed channel.
+func (sr *Hub) Publish(topic string, msg *Message) error {
+	sr.publishLocal(topic, msg)
+
+	return sr.publishRemote(topic, msg)
+}
+
+func (sr *Hub) publishRemote(topic string, msg *Message) error {
+	if sr.pubsub == nil {
+		return nil
+	}
+
+	data, err := encodeEnvelope(sr.nodeID, topic, msg)
+	if err != nil {
+		return err
+	}
+
+	return sr.pubsub.Publish(HubTopic, data)
+}
+
+// Listen receives the broadcasts of other nodes until ctx is done. It
+// fails if the hub cannot subscribe to its pubsub.
+func (sr *Hub) Listen(ctx context.Context) error {
+	if sr.pubsub == nil {
+		<-ctx.Done()
+		return nil
+	}
+
+	unsubscribe, err := sr.pubsub.Subscribe(HubTopic, sr.receive)
+	if err != nil {
+		return fmt.Errorf("hub: subscribe to %s: %w", HubTopic, err)
 	}
-	// sr.Broadcast(msg) // TODO: send to pubsub
+	defer unsubscribe()
+
+	<-ctx.Done()
+
 	return nil
 }
 
-func (sr *Hub) Listen(ctx context.Context) {
-	// TODO add listener for pubsub
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		}
+// receive handles a message published by any node, skipping the ones
+// this node published itself since those were already sent locally.
+func (sr *Hub) receive(data []byte) {
+	env, msg, err := decodeEnvelope(data)
+	if err != nil || env.Node == sr.nodeID {
+		return
+	}
+
+	if env.Topic == "" {
+		sr.broadcast(msg)
+		return
 	}
+
+	sr.publishLocal(env.Topic, msg)
+}
+
+// broadcast sends the message to all servers of this node.
+func (sr *Hub) broadcast(msg *Message) {
+	sr.mu.RLock()
+	servers := make([]broadcaster, 0, len(sr.servers))
+	for s := range sr.servers {
+		servers = append(servers, s)
+	}
+	sr.mu.RUnlock()
+
+	for _, s := range servers {
+		s.BroadcastAll(msg)
+	}
+}
+
+// publishLocal delivers msg to the subscribers of topic on this node.
+// A subscriber failing to handle the message does not affect the others.
+func (sr *Hub) publishLocal(topic string, msg *Message) {
+	sr.mu.RLock()
+	subs := make([]subscriber, 0, len(sr.topics[topic]))
+	for sub := range sr.topics[topic] {
+		subs = append(subs, sub)
+	}
+	sr.mu.RUnlock()
+
+	for _, sub := range subs {
+		sub.server.Broadcast(&Message{
+			Topic:   sub.topic,
+			Event:   msg.Event,
+			Payload: msg.Payload,
+		})
+	}
+}
+
+func encodeEnvelope(node, topic string, msg *Message) ([]byte, error) {
+	data, err := encode(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&envelope{
+		Node:    node,
+		Topic:   topic,
+		Message: data,
+	})
+}
+
+func decodeEnvelope(data []byte) (*envelope, *Message, error) {
+	env := &envelope{}
+
+	err := json.Unmarshal(data, env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msg, err := decode(env.Message)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return env, msg, nil
 }
@@ -0,0 +1,296 @@
+package channel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-live-view/go-live-view/channel"
+	"github.com/go-live-view/go-live-view/channel/pubsub/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	messages []*channel.Message
+}
+
+func (r *recorder) Broadcast(msg *channel.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+func (r *recorder) BroadcastAll(msg *channel.Message) {
+	r.Broadcast(msg)
+}
+
+func (r *recorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = nil
+}
+
+func (r *recorder) events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []string{}
+	for _, msg := range r.messages {
+		events = append(events, msg.Event)
+	}
+	return events
+}
+
+func TestHubPubSub(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ps := memory.New()
+
+	hubA := channel.NewHub(channel.WithPubSub(ps), channel.WithNodeID("a"))
+	hubB := channel.NewHub(channel.WithPubSub(ps), channel.WithNodeID("b"))
+
+	go hubA.Listen(ctx)
+	go hubB.Listen(ctx)
+
+	a := &recorder{}
+	b := &recorder{}
+	hubA.Add(a)
+	hubB.Add(b)
+
+	// wait for both hubs to subscribe
+	assert.Eventually(t, func() bool {
+		hubA.WriteMessage(&channel.Message{Topic: "lv:1", Event: "ping"})
+		return len(b.events()) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	a.reset()
+	b.reset()
+
+	err := hubA.WriteMessage(&channel.Message{Topic: "lv:1", Event: "from-a"})
+	assert.NoError(t, err)
+
+	err = hubB.WriteMessage(&channel.Message{Topic: "lv:1", Event: "from-b"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"from-a", "from-b"}, a.events())
+	assert.Equal(t, []string{"from-a", "from-b"}, b.events())
+}
+
+func TestHubWithoutPubSub(t *testing.T) {
+	hub := channel.NewHub()
+
+	r := &recorder{}
+	hub.Add(r)
+
+	err := hub.WriteMessage(&channel.Message{Topic: "lv:1", Event: "local"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"local"}, r.events())
+
+	hub.Remove(r)
+
+	err = hub.WriteMessage(&channel.Message{Topic: "lv:1", Event: "removed"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"local"}, r.events())
+}
+
+func TestHubTopics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ps := memory.New()
+
+	hubA := channel.NewHub(channel.WithPubSub(ps))
+	hubB := channel.NewHub(channel.WithPubSub(ps))
+
+	go hubA.Listen(ctx)
+	go hubB.Listen(ctx)
+
+	a := &recorder{}
+	b := &recorder{}
+	c := &recorder{}
+
+	hubA.Subscribe("room:1", a, "lv:a")
+	hubA.Subscribe("room:2", c, "lv:c")
+	hubB.Subscribe("room:1", b, "lv:b")
+
+	assert.Eventually(t, func() bool {
+		hubA.Publish("room:1", &channel.Message{Event: "ping"})
+		return len(b.events()) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	a.reset()
+	b.reset()
+
+	err := hubB.Publish("room:1", &channel.Message{Event: "hello"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"hello"}, a.events())
+	assert.Equal(t, []string{"hello"}, b.events())
+	assert.Equal(t, []string{}, c.events())
+	assert.Equal(t, "lv:a", a.messages[0].Topic)
+	assert.Equal(t, "lv:b", b.messages[0].Topic)
+
+	hubA.UnsubscribeAll(a, "lv:a")
+	hubB.Unsubscribe("room:1", b, "lv:b")
+
+	err = hubA.Publish("room:1", &channel.Message{Event: "gone"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"hello"}, a.events())
+	assert.Equal(t, []string{"hello"}, b.events())
+
+	hubA.Remove(c)
+
+	err = hubA.Publish("room:2", &channel.Message{Event: "removed"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, c.events())
+}
+
+// downPubSub fails like a pubsub that cannot reach its broker.
+type downPubSub struct{}
+
+func (downPubSub) Publish(string, []byte) error { return errors.New("broker down") }
+
+func (downPubSub) Subscribe(string, func([]byte)) (func(), error) {
+	return nil, errors.New("broker down")
+}
+
+func TestHubListenError(t *testing.T) {
+	h := channel.NewHub(channel.WithPubSub(downPubSub{}))
+
+	err := h.Listen(context.Background())
+	assert.EqualError(t, err, "hub: subscribe to go-live-view:hub: broker down")
+}
+
+// pipeConn reads the messages sent on in until it is closed.
+type pipeConn struct {
+	in chan []any
+}
+
+func (c *pipeConn) ReadMessage() ([]byte, error) {
+	msg, ok := <-c.in
+	if !ok {
+		return nil, io.EOF
+	}
+
+	return json.Marshal(msg)
+}
+
+func (c *pipeConn) WriteMessage(data []byte) error {
+	return nil
+}
+
+// shoutChannel broadcasts the events it is sent to all channels and
+// records the broadcasts it receives.
+type shoutChannel struct {
+	name     string
+	received *recorder
+}
+
+func (c *shoutChannel) Join(s channel.Socket, p any) error {
+	return s.Push("", nil)
+}
+
+func (c *shoutChannel) Leave(s channel.Socket) error {
+	return nil
+}
+
+func (c *shoutChannel) Message(s channel.Socket, event string, p any) error {
+	return s.PushBroadcast(event, p)
+}
+
+func (c *shoutChannel) Broadcast(s channel.Socket, event string, p any) error {
+	return c.received.Broadcast(&channel.Message{Event: c.name + ": " + event})
+}
+
+func TestHubServers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ps := memory.New()
+
+	hubA := channel.NewHub(channel.WithPubSub(ps), channel.WithNodeID("a"))
+	hubB := channel.NewHub(channel.WithPubSub(ps), channel.WithNodeID("b"))
+
+	go hubA.Listen(ctx)
+	go hubB.Listen(ctx)
+
+	received := &recorder{}
+
+	var mu sync.Mutex
+	var reported []string
+
+	var wg sync.WaitGroup
+	conns := map[string]*pipeConn{}
+	for _, name := range []string{"a1", "a2", "b1"} {
+		hub := hubA
+		if name == "b1" {
+			hub = hubB
+		}
+
+		conn := &pipeConn{in: make(chan []any, 8)}
+		conns[name] = conn
+
+		s := channel.NewServer(conn, hub, channel.WithErrorHandler(func(msg *channel.Message, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			reported = append(reported, msg.Event+": "+err.Error())
+		}))
+		s.Route("test:*", func() channel.Channel {
+			return &shoutChannel{name: name, received: received}
+		})
+		hub.Add(s)
+
+		// each channel has its own topic, like the per-render topics of
+		// liveviews.
+		conn.in <- []any{"1", "1", "test:" + name, "phx_join", nil}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Listen(ctx)
+		}()
+	}
+
+	// wait for both hubs to subscribe
+	assert.Eventually(t, func() bool {
+		conns["a1"].in <- []any{"1", "2", "test:a1", "ping", nil}
+		return slices.Contains(received.events(), "b1: ping")
+	}, time.Second, 10*time.Millisecond)
+
+	conns["a1"].in <- []any{"1", "3", "test:a1", "shout", nil}
+
+	// pings sent while waiting may still arrive.
+	shouts := func() []string {
+		return slices.DeleteFunc(received.events(), func(event string) bool {
+			return strings.HasSuffix(event, ": ping")
+		})
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(shouts()) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	events := shouts()
+	slices.Sort(events)
+	assert.Equal(t, []string{"a1: shout", "a2: shout", "b1: shout"}, events)
+
+	for _, conn := range conns {
+		close(conn.in)
+	}
+	wg.Wait()
+
+	assert.Empty(t, reported)
+}
@@ -0,0 +1,9 @@
+package channel
+
+// PubSub distributes messages between hubs running in different
+// processes. Implementations must deliver a published message to every
+// subscriber of the topic, including subscribers in the publishing process.
+type PubSub interface {
+	Publish(topic string, data []byte) error
+	Subscribe(topic string, handler func([]byte)) (func(), error)
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/go-live-view/go-live-view/channel"
+)
+
+var _ channel.PubSub = (*PubSub)(nil)
+
+type subscription struct {
+	handler func([]byte)
+}
+
+// PubSub delivers messages to the subscribers of the same process.
+type PubSub struct {
+	mu     sync.RWMutex
+	topics map[string]map[*subscription]struct{}
+}
+
+// New returns an in-process PubSub. Hubs sharing the same instance
+// receive each other's broadcasts.
+func New() *PubSub {
+	return &PubSub{
+		topics: make(map[string]map[*subscription]struct{}),
+	}
+}
+
+func (m *PubSub) Publish(topic string, data []byte) error {
+	m.mu.RLock()
+	subs := make([]*subscription, 0, len(m.topics[topic]))
+	for sub := range m.topics[topic] {
+		subs = append(subs, sub)
+	}
+	m.mu.RUnlock()
+
+	for _, sub := range subs {
+		sub.handler(data)
+	}
+
+	return nil
+}
+
+func (m *PubSub) Subscribe(topic string, handler func([]byte)) (func(), error) {
+	sub := &subscription{handler: handler}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.topics[topic] == nil {
+		m.topics[topic] = make(map[*subscription]struct{})
+	}
+	m.topics[topic][sub] = struct{}{}
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		delete(m.topics[topic], sub)
+		if len(m.topics[topic]) == 0 {
+			delete(m.topics, topic)
+		}
+	}, nil
+}
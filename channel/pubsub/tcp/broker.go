@@ -0,0 +1,160 @@
+package tcp
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+)
+
+const (
+	opSubscribe   = "sub"
+	opUnsubscribe = "unsub"
+	opPublish     = "pub"
+)
+
+// frame is the newline delimited JSON message exchanged between brokers
+// and clients.
+type frame struct {
+	Op    string `json:"op"`
+	Topic string `json:"topic"`
+	Data  []byte `json:"data,omitempty"`
+}
+
+// Broker relays published messages to every connected client subscribed
+// to the topic. Run one broker per cluster, reachable over TCP or a Unix
+// socket by every node.
+type Broker struct {
+	mu        sync.RWMutex
+	listeners map[net.Listener]struct{}
+	peers     map[*peer]struct{}
+	closed    bool
+}
+
+type peer struct {
+	conn net.Conn
+
+	mu     sync.Mutex
+	enc    *json.Encoder
+	topics map[string]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		listeners: make(map[net.Listener]struct{}),
+		peers:     make(map[*peer]struct{}),
+	}
+}
+
+// Serve accepts clients on l until the listener or the broker is closed.
+func (b *Broker) Serve(l net.Listener) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return net.ErrClosed
+	}
+	b.listeners[l] = struct{}{}
+	b.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		go b.handle(conn)
+	}
+}
+
+// Close stops all listeners and disconnects all clients.
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	for l := range b.listeners {
+		l.Close()
+	}
+	for p := range b.peers {
+		p.conn.Close()
+	}
+
+	return nil
+}
+
+func (b *Broker) handle(conn net.Conn) {
+	p := &peer{
+		conn:   conn,
+		enc:    json.NewEncoder(conn),
+		topics: make(map[string]struct{}),
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		conn.Close()
+		return
+	}
+	b.peers[p] = struct{}{}
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		delete(b.peers, p)
+		b.mu.Unlock()
+		conn.Close()
+	}()
+
+	dec := json.NewDecoder(conn)
+	for {
+		f := &frame{}
+		err := dec.Decode(f)
+		if err != nil {
+			return
+		}
+
+		switch f.Op {
+		case opSubscribe:
+			p.mu.Lock()
+			p.topics[f.Topic] = struct{}{}
+			p.mu.Unlock()
+		case opUnsubscribe:
+			p.mu.Lock()
+			delete(p.topics, f.Topic)
+			p.mu.Unlock()
+		case opPublish:
+			b.publish(f)
+		}
+	}
+}
+
+func (b *Broker) publish(f *frame) {
+	b.mu.RLock()
+	peers := make([]*peer, 0, len(b.peers))
+	for p := range b.peers {
+		peers = append(peers, p)
+	}
+	b.mu.RUnlock()
+
+	for _, p := range peers {
+		p.send(f)
+	}
+}
+
+func (p *peer) send(f *frame) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.topics[f.Topic]; !ok {
+		return
+	}
+
+	err := p.enc.Encode(f)
+	if err != nil {
+		p.conn.Close()
+	}
+}
@@ -0,0 +1,201 @@
+package tcp
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-live-view/go-live-view/channel"
+)
+
+var _ channel.PubSub = (*PubSub)(nil)
+
+var errNotConnected = errors.New("pubsub: not connected to broker")
+
+const (
+	minBackoff = 100 * time.Millisecond
+	maxBackoff = 5 * time.Second
+)
+
+type subscription struct {
+	handler func([]byte)
+}
+
+// PubSub is a client of a Broker, see Dial.
+type PubSub struct {
+	network string
+	address string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	enc    *json.Encoder
+	topics map[string]map[*subscription]struct{}
+	closed chan struct{}
+}
+
+// Dial connects to a Broker listening on the given network address, e.g.
+// Dial("tcp", "pubsub:4000") or Dial("unix", "/run/lv.sock"). The client
+// reconnects and resubscribes when the connection to the broker drops.
+func Dial(network, address string) (*PubSub, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := &PubSub{
+		network: network,
+		address: address,
+		conn:    conn,
+		enc:     json.NewEncoder(conn),
+		topics:  make(map[string]map[*subscription]struct{}),
+		closed:  make(chan struct{}),
+	}
+
+	go ps.read(conn)
+
+	return ps, nil
+}
+
+func (t *PubSub) Publish(topic string, data []byte) error {
+	return t.send(&frame{
+		Op:    opPublish,
+		Topic: topic,
+		Data:  data,
+	})
+}
+
+func (t *PubSub) Subscribe(topic string, handler func([]byte)) (func(), error) {
+	sub := &subscription{handler: handler}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.topics[topic] == nil {
+		err := t.write(&frame{Op: opSubscribe, Topic: topic})
+		if err != nil {
+			return nil, err
+		}
+		t.topics[topic] = make(map[*subscription]struct{})
+	}
+	t.topics[topic][sub] = struct{}{}
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		delete(t.topics[topic], sub)
+		if len(t.topics[topic]) == 0 {
+			delete(t.topics, topic)
+			t.write(&frame{Op: opUnsubscribe, Topic: topic})
+		}
+	}, nil
+}
+
+// Close disconnects from the broker.
+func (t *PubSub) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	select {
+	case <-t.closed:
+		return nil
+	default:
+	}
+
+	close(t.closed)
+
+	if t.conn != nil {
+		return t.conn.Close()
+	}
+
+	return nil
+}
+
+func (t *PubSub) send(f *frame) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.write(f)
+}
+
+// write must be called with t.mu held.
+func (t *PubSub) write(f *frame) error {
+	if t.conn == nil {
+		return errNotConnected
+	}
+
+	return t.enc.Encode(f)
+}
+
+func (t *PubSub) read(conn net.Conn) {
+	dec := json.NewDecoder(conn)
+	for {
+		f := &frame{}
+		err := dec.Decode(f)
+		if err != nil {
+			t.reconnect(conn)
+			return
+		}
+
+		if f.Op != opPublish {
+			continue
+		}
+
+		t.mu.Lock()
+		subs := make([]*subscription, 0, len(t.topics[f.Topic]))
+		for sub := range t.topics[f.Topic] {
+			subs = append(subs, sub)
+		}
+		t.mu.Unlock()
+
+		for _, sub := range subs {
+			sub.handler(f.Data)
+		}
+	}
+}
+
+func (t *PubSub) reconnect(old net.Conn) {
+	t.mu.Lock()
+	if t.conn == old {
+		t.conn = nil
+		t.enc = nil
+	}
+	t.mu.Unlock()
+	old.Close()
+
+	backoff := minBackoff
+	for {
+		select {
+		case <-t.closed:
+			return
+		case <-time.After(backoff):
+		}
+
+		conn, err := net.Dial(t.network, t.address)
+		if err != nil {
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+
+		t.mu.Lock()
+		select {
+		case <-t.closed:
+			t.mu.Unlock()
+			conn.Close()
+			return
+		default:
+		}
+
+		t.conn = conn
+		t.enc = json.NewEncoder(conn)
+		for topic := range t.topics {
+			t.write(&frame{Op: opSubscribe, Topic: topic})
+		}
+		t.mu.Unlock()
+
+		go t.read(conn)
+		return
+	}
+}
@@ -0,0 +1,117 @@
+package tcp
+
+import (
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type inbox struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (i *inbox) handle(data []byte) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.msgs = append(i.msgs, string(data))
+}
+
+func (i *inbox) get() []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return append([]string{}, i.msgs...)
+}
+
+func TestPubSub(t *testing.T) {
+	tt := []struct {
+		network string
+		address string
+	}{
+		{"tcp", "127.0.0.1:0"},
+		{"unix", filepath.Join(t.TempDir(), "pubsub.sock")},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.network, func(t *testing.T) {
+			l, err := net.Listen(tc.network, tc.address)
+			assert.NoError(t, err)
+
+			broker := NewBroker()
+			defer broker.Close()
+			go broker.Serve(l)
+
+			a, err := Dial(tc.network, l.Addr().String())
+			assert.NoError(t, err)
+			defer a.Close()
+
+			b, err := Dial(tc.network, l.Addr().String())
+			assert.NoError(t, err)
+			defer b.Close()
+
+			ia := &inbox{}
+			ib := &inbox{}
+
+			_, err = a.Subscribe("topic", ia.handle)
+			assert.NoError(t, err)
+			unsubscribe, err := b.Subscribe("topic", ib.handle)
+			assert.NoError(t, err)
+
+			assert.Eventually(t, func() bool {
+				a.Publish("topic", []byte("hello"))
+				return len(ia.get()) > 0 && len(ib.get()) > 0
+			}, time.Second, 10*time.Millisecond)
+
+			a.Publish("other", []byte("ignored"))
+
+			unsubscribe()
+
+			assert.Eventually(t, func() bool {
+				before := len(ib.get())
+				a.Publish("topic", []byte("after"))
+				time.Sleep(10 * time.Millisecond)
+				return len(ib.get()) == before
+			}, time.Second, 10*time.Millisecond)
+
+			assert.NotContains(t, ia.get(), "ignored")
+			assert.NotContains(t, ib.get(), "after")
+		})
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := l.Addr().String()
+
+	broker := NewBroker()
+	go broker.Serve(l)
+
+	ps, err := Dial("tcp", addr)
+	assert.NoError(t, err)
+	defer ps.Close()
+
+	in := &inbox{}
+	_, err = ps.Subscribe("topic", in.handle)
+	assert.NoError(t, err)
+
+	broker.Close()
+
+	l, err = net.Listen("tcp", addr)
+	assert.NoError(t, err)
+
+	broker = NewBroker()
+	defer broker.Close()
+	go broker.Serve(l)
+
+	assert.Eventually(t, func() bool {
+		ps.Publish("topic", []byte("again"))
+		return len(in.get()) > 0
+	}, 5*time.Second, 50*time.Millisecond)
+}
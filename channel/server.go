@@ -2,11 +2,16 @@ package channel
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
+	"time"
 )
 
+type ServerOption func(*server)
+
 type server struct {
 	mu sync.RWMutex
 
@@ -14,17 +19,53 @@ type server struct {
 	c        *conn
 	matchers map[string]func() Channel
 	channels map[string]Channel
+	joinRefs map[string]string
+	onError  ErrorHandler
+	logger   *slog.Logger
 }
 
-func NewServer(c Conn, h *Hub) *server {
-	return &server{
+func NewServer(c Conn, h *Hub, opts ...ServerOption) *server {
+	s := &server{
 		h:        h,
 		c:        newConnection(c),
 		matchers: make(map[string]func() Channel),
 		channels: make(map[string]Channel),
+		joinRefs: make(map[string]string),
+		logger:   slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.onError == nil {
+		s.onError = s.logError
+	}
+
+	return s
+}
+
+// WithErrorHandler reports the errors of the messages to f instead of
+// logging them.
+func WithErrorHandler(f ErrorHandler) ServerOption {
+	return func(s *server) {
+		s.onError = f
+	}
+}
+
+// WithLogger sets the logger of the server. Errors are logged at the
+// error level unless WithErrorHandler is set, every message received and
+// pushed at the debug level. It defaults to slog.Default.
+func WithLogger(logger *slog.Logger) ServerOption {
+	return func(s *server) {
+		s.logger = logger
 	}
 }
 
+func (s *server) logError(msg *Message, err error) {
+	s.logger.Error("channel error", "topic", msg.Topic, "event", msg.Event, "error", err)
+}
+
 func (s *server) Route(topic string, factory func() Channel) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -42,11 +83,34 @@ func (s *server) Broadcast(msg *Message) error {
 		return err
 	}
 
+	if msg.JoinRef == "" {
+		msg.JoinRef = s.getJoinRef(msg.Topic)
+	}
+
 	sock := NewSocket(s, msg)
 
 	return mChan.Broadcast(sock, msg.Event, msg.Payload)
 }
 
+// BroadcastAll sends msg to every joined channel under its own topic. A
+// channel failing to handle it does not affect the others.
+func (s *server) BroadcastAll(msg *Message) {
+	s.mu.RLock()
+	topics := make([]string, 0, len(s.channels))
+	for topic := range s.channels {
+		topics = append(topics, topic)
+	}
+	s.mu.RUnlock()
+
+	for _, topic := range topics {
+		s.Broadcast(&Message{
+			Topic:   topic,
+			Event:   msg.Event,
+			Payload: msg.Payload,
+		})
+	}
+}
+
 func (s *server) PushBroadcast(msg *Message) error {
 	if s.h == nil {
 		return fmt.Errorf("no server available")
@@ -55,44 +119,88 @@ func (s *server) PushBroadcast(msg *Message) error {
 	return s.h.WriteMessage(msg)
 }
 
+// Subscribe subscribes the channel joined under channelTopic to topic.
+func (s *server) Subscribe(channelTopic, topic string) error {
+	if s.h == nil {
+		return fmt.Errorf("no server available")
+	}
+
+	s.h.Subscribe(topic, s, channelTopic)
+
+	return nil
+}
+
+// Unsubscribe reverts Subscribe.
+func (s *server) Unsubscribe(channelTopic, topic string) error {
+	if s.h == nil {
+		return fmt.Errorf("no server available")
+	}
+
+	s.h.Unsubscribe(topic, s, channelTopic)
+
+	return nil
+}
+
+// Publish sends msg to all channels subscribed to topic.
+func (s *server) Publish(topic string, msg *Message) error {
+	if s.h == nil {
+		return fmt.Errorf("no server available")
+	}
+
+	return s.h.Publish(topic, msg)
+}
+
 func (s *server) Push(msg *Message) error {
-	return s.c.WriteMessage(msg)
+	size, err := s.c.WriteMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug("channel push", "topic", msg.Topic, "event", msg.Event, "ref", msg.Ref, "size", size)
+
+	return nil
 }
 
+// Listen handles the messages of the connection until it is closed or
+// ctx is done. The channels still joined then are left.
 func (s *server) Listen(ctx context.Context) {
+	defer s.leaveAll()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			msg, err := s.c.ReadMessage()
+			msg, size, err := s.c.ReadMessage()
 			if err != nil {
 				return
 			}
 
-			switch msg.Event {
-			case "heartbeat":
-				err := s.handleHeartbeat(msg)
-				if err != nil {
-					s.handleError(msg, err)
-				}
-			case "phx_join":
-				err := s.handleJoin(msg)
-				if err != nil {
-					s.handleError(msg, err)
-				}
-			case "phx_leave":
-				err := s.handleLeave(msg)
-				if err != nil {
-					s.handleError(msg, err)
-				}
-			default:
-				err := s.handleMessage(msg)
-				if err != nil {
-					s.handleError(msg, err)
+			start := time.Now()
+
+			err = Recover(func() error {
+				switch msg.Event {
+				case "heartbeat":
+					return s.handleHeartbeat(msg)
+				case "phx_join":
+					return s.handleJoin(msg)
+				case "phx_leave":
+					return s.handleLeave(msg)
+				default:
+					return s.handleMessage(msg)
 				}
+			})
+			if err != nil {
+				s.handleError(msg, err)
 			}
 
+			s.logger.Debug("channel message",
+				"topic", msg.Topic,
+				"event", msg.Event,
+				"ref", msg.Ref,
+				"size", size,
+				"duration", time.Since(start),
+			)
 		}
 	}
 }
@@ -120,10 +228,11 @@ func (s *server) handleJoin(msg *Message) error {
 
 	err = mChan.Join(sock, msg.Payload)
 	if err != nil {
+		s.deleteChannel(msg.Topic)
 		return err
 	}
 
-	s.setChannel(msg.Topic, mChan)
+	s.setChannel(msg.Topic, msg.JoinRef, mChan)
 
 	return nil
 }
@@ -146,6 +255,25 @@ func (s *server) handleLeave(msg *Message) error {
 	return nil
 }
 
+// leaveAll leaves all joined channels, e.g. when the client disconnected
+// without leaving them. Replies to the client are expected to fail.
+func (s *server) leaveAll() {
+	s.mu.RLock()
+	topics := make(map[string]string, len(s.joinRefs))
+	for topic, joinRef := range s.joinRefs {
+		topics[topic] = joinRef
+	}
+	s.mu.RUnlock()
+
+	for topic, joinRef := range topics {
+		s.handleLeave(&Message{
+			JoinRef: joinRef,
+			Topic:   topic,
+			Event:   "phx_leave",
+		})
+	}
+}
+
 func (s *server) handleMessage(msg *Message) error {
 	mChan, err := s.getChannel(msg.Topic)
 	if err != nil {
@@ -157,21 +285,63 @@ func (s *server) handleMessage(msg *Message) error {
 	return mChan.Message(sock, msg.Event, msg.Payload)
 }
 
+// handleError reports err and replies to msg with its reason. A panic
+// crashes the channel: the client is sent a phx_error and rejoins.
 func (s *server) handleError(msg *Message, err error) {
+	var panicErr *PanicError
+	crashed := errors.As(err, &panicErr)
+
+	if crashed && msg.Event == "phx_join" {
+		err = &ReplyError{
+			Response: map[string]any{"reason": ReasonJoinCrashed},
+			Err:      err,
+		}
+		crashed = false
+	}
+
+	response, report := response(err)
+	if report {
+		s.onError(msg, err)
+	}
+
+	if crashed {
+		s.crash(msg)
+		return
+	}
+
+	// messages without a ref, like broadcasts, expect no reply.
+	if msg.Ref == "" {
+		return
+	}
+
 	pushErr := s.Push(&Message{
 		JoinRef: msg.JoinRef,
 		Ref:     msg.Ref,
 		Topic:   msg.Topic,
 		Event:   "phx_reply",
 		Payload: map[string]any{
-			"status": "error",
-			"response": map[string]any{
-				"reason": err.Error(),
-			},
+			"status":   "error",
+			"response": response,
 		},
 	})
 	if pushErr != nil {
-		fmt.Println(pushErr)
+		s.onError(msg, pushErr)
+	}
+}
+
+// crash removes the channel of msg and tells the client it errored.
+func (s *server) crash(msg *Message) {
+	s.deleteChannel(msg.Topic)
+
+	err := s.Push(&Message{
+		JoinRef: msg.JoinRef,
+		Ref:     msg.JoinRef,
+		Topic:   msg.Topic,
+		Event:   "phx_error",
+		Payload: map[string]any{},
+	})
+	if err != nil {
+		s.onError(msg, err)
 	}
 }
 
@@ -182,7 +352,7 @@ func (s *server) match(topic string) (Channel, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("no channel found for topic %s", topic)
+	return nil, Reason(ReasonUnmatchedTopic, fmt.Errorf("no channel found for topic %s", topic))
 }
 
 func (s *server) getChannel(topic string) (Channel, error) {
@@ -191,24 +361,36 @@ func (s *server) getChannel(topic string) (Channel, error) {
 
 	channel, ok := s.channels[topic]
 	if !ok {
-		return nil, fmt.Errorf("no channel found for topic %s", topic)
+		return nil, Reason(ReasonUnmatchedTopic, fmt.Errorf("no channel found for topic %s", topic))
 	}
 
 	return channel, nil
 }
 
-func (s *server) setChannel(topic string, c Channel) {
+func (s *server) getJoinRef(topic string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.joinRefs[topic]
+}
+
+func (s *server) setChannel(topic, joinRef string, c Channel) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.channels[topic] = c
+	s.joinRefs[topic] = joinRef
 }
 
 func (s *server) deleteChannel(topic string) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	delete(s.channels, topic)
+	delete(s.joinRefs, topic)
+	s.mu.Unlock()
+
+	if s.h != nil {
+		s.h.UnsubscribeAll(s, topic)
+	}
 }
 
 func match(pattern, path string) bool {
@@ -0,0 +1,235 @@
+package channel_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/go-live-view/go-live-view/channel"
+	"github.com/stretchr/testify/assert"
+)
+
+// scriptConn reads the given messages, then fails like a closed
+// connection.
+type scriptConn struct {
+	mu     sync.Mutex
+	in     [][]any
+	writes [][]any
+	closed bool
+}
+
+func (c *scriptConn) ReadMessage() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.in) == 0 {
+		c.closed = true
+		return nil, io.EOF
+	}
+
+	msg := c.in[0]
+	c.in = c.in[1:]
+
+	return json.Marshal(msg)
+}
+
+func (c *scriptConn) WriteMessage(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return io.ErrClosedPipe
+	}
+
+	var msg []any
+	err := json.Unmarshal(data, &msg)
+	if err != nil {
+		return err
+	}
+
+	c.writes = append(c.writes, msg)
+	return nil
+}
+
+type failingChannel struct{}
+
+func (failingChannel) Join(s channel.Socket, p any) error {
+	if p == "boom" {
+		panic("join boom")
+	}
+
+	return s.Push("", nil)
+}
+
+func (failingChannel) Leave(s channel.Socket) error {
+	return s.Push("", nil)
+}
+
+func (failingChannel) Message(s channel.Socket, event string, p any) error {
+	switch event {
+	case "boom":
+		panic("boom")
+	case "fail":
+		return errors.New("database is down")
+	case "unauthorized":
+		return channel.Reason(channel.ReasonUnauthorized, errors.New("bad token"))
+	case "redirect":
+		return &channel.ReplyError{Response: map[string]any{"redirect": map[string]any{"to": "/"}}}
+	}
+
+	return s.Push("", nil)
+}
+
+func (failingChannel) Broadcast(s channel.Socket, event string, p any) error {
+	return nil
+}
+
+func TestServerErrors(t *testing.T) {
+	tt := []struct {
+		name     string
+		messages [][]any
+		last     []any
+		reported []string
+	}{
+		{
+			name: "error",
+			messages: [][]any{
+				{"1", "2", "test:a", "fail", nil},
+			},
+			last: []any{"1", "2", "test:a", "phx_reply", map[string]any{
+				"status":   "error",
+				"response": map[string]any{"reason": "internal error"},
+			}},
+			reported: []string{"fail: database is down"},
+		},
+		{
+			name: "reason",
+			messages: [][]any{
+				{"1", "2", "test:a", "unauthorized", nil},
+			},
+			last: []any{"1", "2", "test:a", "phx_reply", map[string]any{
+				"status":   "error",
+				"response": map[string]any{"reason": "unauthorized"},
+			}},
+			reported: []string{"unauthorized: bad token"},
+		},
+		{
+			name: "reply without error",
+			messages: [][]any{
+				{"1", "2", "test:a", "redirect", nil},
+			},
+			last: []any{"1", "2", "test:a", "phx_reply", map[string]any{
+				"status":   "error",
+				"response": map[string]any{"redirect": map[string]any{"to": "/"}},
+			}},
+		},
+		{
+			name: "panic crashes the channel",
+			messages: [][]any{
+				{"1", "2", "test:a", "boom", nil},
+			},
+			last:     []any{"1", "1", "test:a", "phx_error", map[string]any{}},
+			reported: []string{"boom: panic: boom"},
+		},
+		{
+			name: "crashed channel is gone",
+			messages: [][]any{
+				{"1", "2", "test:a", "boom", nil},
+				{"1", "3", "test:a", "ping", nil},
+			},
+			last: []any{"1", "3", "test:a", "phx_reply", map[string]any{
+				"status":   "error",
+				"response": map[string]any{"reason": "unmatched topic"},
+			}},
+			reported: []string{"boom: panic: boom", "ping: no channel found for topic test:a"},
+		},
+		{
+			name: "panic on join",
+			messages: [][]any{
+				{"5", "5", "test:b", "phx_join", "boom"},
+			},
+			last: []any{"5", "5", "test:b", "phx_reply", map[string]any{
+				"status":   "error",
+				"response": map[string]any{"reason": "join crashed"},
+			}},
+			reported: []string{"phx_join: panic: join boom"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := &scriptConn{
+				in: append([][]any{{"1", "1", "test:a", "phx_join", nil}}, tc.messages...),
+			}
+
+			var reported []string
+			s := channel.NewServer(conn, nil, channel.WithErrorHandler(func(msg *channel.Message, err error) {
+				reported = append(reported, msg.Event+": "+err.Error())
+			}))
+			s.Route("test:*", func() channel.Channel { return failingChannel{} })
+
+			s.Listen(context.Background())
+
+			// the first write replies to the join of test:a.
+			assert.Equal(t, "ok", conn.writes[0][4].(map[string]any)["status"])
+			assert.Equal(t, tc.last, conn.writes[len(conn.writes)-1])
+			assert.Equal(t, tc.reported, reported)
+		})
+	}
+}
+
+func TestServerLogs(t *testing.T) {
+	conn := &scriptConn{
+		in: [][]any{
+			{"1", "1", "test:a", "phx_join", nil},
+			{"1", "2", "test:a", "fail", nil},
+		},
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	s := channel.NewServer(conn, nil, channel.WithLogger(logger))
+	s.Route("test:*", func() channel.Channel { return failingChannel{} })
+
+	s.Listen(context.Background())
+
+	var logs []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+
+		delete(entry, "time")
+		delete(entry, "duration")
+		logs = append(logs, entry)
+	}
+
+	assert.Equal(t, []map[string]any{
+		{"level": "DEBUG", "msg": "channel push", "topic": "test:a", "event": "phx_reply", "ref": "1", "size": float64(60)},
+		{"level": "DEBUG", "msg": "channel message", "topic": "test:a", "event": "phx_join", "ref": "1", "size": float64(34)},
+		{"level": "ERROR", "msg": "channel error", "topic": "test:a", "event": "fail", "error": "database is down"},
+		{"level": "DEBUG", "msg": "channel push", "topic": "test:a", "event": "phx_reply", "ref": "2", "size": float64(88)},
+		{"level": "DEBUG", "msg": "channel message", "topic": "test:a", "event": "fail", "ref": "2", "size": float64(30)},
+	}, logs)
+}
+
+func TestRecover(t *testing.T) {
+	err := channel.Recover(func() error {
+		panic("boom")
+	})
+
+	var panicErr *channel.PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+
+	err = channel.Recover(func() error {
+		return io.EOF
+	})
+	assert.Equal(t, io.EOF, err)
+}
@@ -4,6 +4,10 @@ type Socket interface {
 	Push(string, any) error
 	PushBroadcast(string, any) error
 	PushSelf(string, any) error
+	Subscribe(string) error
+	Unsubscribe(string) error
+	Broadcast(string, string, any) error
+	ReportError(error)
 	Close() error
 }
 
@@ -12,6 +16,7 @@ type socket struct {
 	joinRef string
 	ref     string
 	topic   string
+	event   string
 	replied bool
 }
 
@@ -21,6 +26,7 @@ func NewSocket(s *server, msg *Message) *socket {
 		joinRef: msg.JoinRef,
 		ref:     msg.Ref,
 		topic:   msg.Topic,
+		event:   msg.Event,
 	}
 }
 
@@ -64,6 +70,18 @@ func (s *socket) Push(event string, payload any) error {
 	})
 }
 
+// ReportError handles err like an error returned by the channel, for
+// messages handled after the channel returned, like queued broadcasts.
+// It is reported, and a *PanicError crashes the channel. Nothing is
+// replied.
+func (s *socket) ReportError(err error) {
+	s.server.handleError(&Message{
+		JoinRef: s.joinRef,
+		Topic:   s.topic,
+		Event:   s.event,
+	}, err)
+}
+
 func (s *socket) Close() error {
 	s.server.Close(s.topic)
 
@@ -92,3 +110,22 @@ func (s *socket) PushBroadcast(event string, payload any) error {
 		Payload: payload,
 	})
 }
+
+// Subscribe delivers messages published to topic to this channel.
+func (s *socket) Subscribe(topic string) error {
+	return s.server.Subscribe(s.topic, topic)
+}
+
+// Unsubscribe stops delivering messages published to topic.
+func (s *socket) Unsubscribe(topic string) error {
+	return s.server.Unsubscribe(s.topic, topic)
+}
+
+// Broadcast sends an event to all channels subscribed to topic.
+func (s *socket) Broadcast(topic, event string, payload any) error {
+	return s.server.Publish(topic, &Message{
+		Topic:   s.topic,
+		Event:   event,
+		Payload: payload,
+	})
+}
@@ -1,21 +1,76 @@
 package longpoll
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-live-view/go-live-view/channel"
 )
 
 var _ channel.Transport = (*lpTransport)(nil)
 
-func New(path string) channel.Transport {
-	return &lpTransport{
-		path: path,
+const (
+	defaultPollTimeout = 10 * time.Second
+	defaultIdleTimeout = 20 * time.Second
+	defaultMaxBodySize = 8 << 20
+)
+
+type lpOption func(*lpTransport)
+
+type lpTransport struct {
+	path        string
+	pollTimeout time.Duration
+	idleTimeout time.Duration
+	maxBodySize int64
+
+	mu       sync.Mutex
+	sessions map[string]*lpSession
+}
+
+func New(path string, opts ...lpOption) channel.Transport {
+	t := &lpTransport{
+		path:        path,
+		pollTimeout: defaultPollTimeout,
+		idleTimeout: defaultIdleTimeout,
+		maxBodySize: defaultMaxBodySize,
+		sessions:    make(map[string]*lpSession),
 	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
 }
 
-type lpTransport struct {
-	path string
+// WithPollTimeout sets how long a GET request waits for server messages
+// before answering with 204.
+func WithPollTimeout(d time.Duration) lpOption {
+	return func(t *lpTransport) {
+		t.pollTimeout = d
+	}
+}
+
+// WithIdleTimeout sets how long a session survives without any request
+// from the client before it is closed.
+func WithIdleTimeout(d time.Duration) lpOption {
+	return func(t *lpTransport) {
+		t.idleTimeout = d
+	}
+}
+
+// WithMaxBodySize limits the size of a POST body.
+func WithMaxBodySize(n int64) lpOption {
+	return func(t *lpTransport) {
+		t.maxBodySize = n
+	}
 }
 
 func (l *lpTransport) Path() string {
@@ -23,5 +78,181 @@ func (l *lpTransport) Path() string {
 }
 
 func (t *lpTransport) Serve(handle func(channel.Conn), w http.ResponseWriter, r *http.Request) {
-	panic("not implemented")
+	switch r.Method {
+	case http.MethodGet:
+		t.poll(handle, w, r)
+	case http.MethodPost:
+		t.publish(w, r)
+	case http.MethodOptions:
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (t *lpTransport) poll(handle func(channel.Conn), w http.ResponseWriter, r *http.Request) {
+	sess := t.getSession(r.URL.Query().Get("token"))
+	if sess == nil {
+		sess, err := t.newSession()
+		if err != nil {
+			writeStatus(w, http.StatusInternalServerError, "", nil)
+			return
+		}
+
+		go func() {
+			defer t.closeSession(sess)
+			handle(sess)
+		}()
+
+		// 410 tells the client that the session is new and it can now
+		// open the channel and start polling with the given token.
+		writeStatus(w, http.StatusGone, sess.token, nil)
+		return
+	}
+
+	sess.begin()
+	defer sess.end()
+
+	messages, err := sess.wait(r.Context(), t.pollTimeout)
+	if err != nil {
+		writeStatus(w, http.StatusGone, "", nil)
+		return
+	}
+
+	if len(messages) == 0 {
+		writeStatus(w, http.StatusNoContent, sess.token, nil)
+		return
+	}
+
+	err = writeStatus(w, http.StatusOK, sess.token, messages)
+	if err != nil {
+		sess.requeue(messages)
+	}
+}
+
+func (t *lpTransport) publish(w http.ResponseWriter, r *http.Request) {
+	sess := t.getSession(r.URL.Query().Get("token"))
+	if sess == nil {
+		writeStatus(w, http.StatusGone, "", nil)
+		return
+	}
+
+	sess.begin()
+	defer sess.end()
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, t.maxBodySize))
+	if err != nil {
+		writeStatus(w, http.StatusInternalServerError, sess.token, nil)
+		return
+	}
+
+	for _, msg := range splitMessages(r.Header.Get("Content-Type"), body) {
+		err := sess.push(r.Context(), msg)
+		if err != nil {
+			writeStatus(w, http.StatusGone, "", nil)
+			return
+		}
+	}
+
+	writeStatus(w, http.StatusOK, sess.token, nil)
+}
+
+func (t *lpTransport) newSession() (*lpSession, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	sess := newSession(token, t.idleTimeout)
+	sess.timer = time.AfterFunc(t.idleTimeout, func() {
+		// the timer may fire while a request begins.
+		if sess.busy() {
+			return
+		}
+		t.closeSession(sess)
+	})
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sessions[token] = sess
+
+	return sess, nil
+}
+
+func (t *lpTransport) getSession(token string) *lpSession {
+	if token == "" {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.sessions[token]
+}
+
+func (t *lpTransport) closeSession(sess *lpSession) {
+	t.mu.Lock()
+	delete(t.sessions, sess.token)
+	t.mu.Unlock()
+
+	sess.close()
+}
+
+// splitMessages splits a POST body into individual channel messages. The
+// Phoenix client batches messages as newline delimited JSON and encodes
+// binary messages as base64.
+func splitMessages(contentType string, body []byte) [][]byte {
+	if !strings.HasPrefix(contentType, "application/x-ndjson") {
+		return [][]byte{body}
+	}
+
+	messages := [][]byte{}
+
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		if line[0] == '[' {
+			messages = append(messages, line)
+			continue
+		}
+
+		data, err := base64.StdEncoding.DecodeString(string(line))
+		if err != nil {
+			continue
+		}
+		messages = append(messages, data)
+	}
+
+	return messages
+}
+
+func writeStatus(w http.ResponseWriter, status int, token string, messages []string) error {
+	resp := map[string]any{
+		"status": status,
+	}
+
+	if token != "" {
+		resp["token"] = token
+	}
+
+	if messages != nil {
+		resp["messages"] = messages
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(resp)
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 24)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
@@ -0,0 +1,191 @@
+package longpoll
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-live-view/go-live-view/channel"
+	"github.com/stretchr/testify/assert"
+)
+
+type response struct {
+	Status   int      `json:"status"`
+	Token    string   `json:"token"`
+	Messages []string `json:"messages"`
+}
+
+func do(t *testing.T, tr channel.Transport, handle func(channel.Conn), method, target, contentType, body string) response {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+
+	tr.Serve(handle, rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp response
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+
+	return resp
+}
+
+// echo writes back every message it reads until the connection closes.
+func echo(c channel.Conn) {
+	for {
+		msg, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		c.WriteMessage(msg)
+	}
+}
+
+func TestLongPoll(t *testing.T) {
+	tr := New("/live/longpoll", WithPollTimeout(50*time.Millisecond))
+
+	resp := do(t, tr, echo, http.MethodGet, "/live/longpoll?vsn=2.0.0", "", "")
+	assert.Equal(t, http.StatusGone, resp.Status)
+	assert.NotEmpty(t, resp.Token)
+
+	token := resp.Token
+
+	resp = do(t, tr, echo, http.MethodGet, "/live/longpoll?token="+token, "", "")
+	assert.Equal(t, http.StatusNoContent, resp.Status)
+	assert.Equal(t, token, resp.Token)
+
+	resp = do(t, tr, echo, http.MethodPost, "/live/longpoll?token="+token, "application/x-ndjson",
+		`["1","1","lv:1","phx_join",{}]`+"\n"+`["1","2","lv:1","event",{}]`,
+	)
+	assert.Equal(t, http.StatusOK, resp.Status)
+
+	assert.Eventually(t, func() bool {
+		resp = do(t, tr, echo, http.MethodGet, "/live/longpoll?token="+token, "", "")
+		return resp.Status == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, []string{
+		`["1","1","lv:1","phx_join",{}]`,
+		`["1","2","lv:1","event",{}]`,
+	}, resp.Messages)
+}
+
+func TestLongPollUnknownToken(t *testing.T) {
+	tr := New("/live/longpoll")
+
+	resp := do(t, tr, echo, http.MethodPost, "/live/longpoll?token=unknown", "application/x-ndjson", `[]`)
+	assert.Equal(t, http.StatusGone, resp.Status)
+}
+
+func TestLongPollIdleExpiry(t *testing.T) {
+	tr := New("/live/longpoll", WithIdleTimeout(20*time.Millisecond))
+
+	closed := make(chan struct{})
+	handle := func(c channel.Conn) {
+		echo(c)
+		close(closed)
+	}
+
+	resp := do(t, tr, handle, http.MethodGet, "/live/longpoll", "", "")
+	assert.Equal(t, http.StatusGone, resp.Status)
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("session was not expired")
+	}
+
+	resp = do(t, tr, handle, http.MethodPost, "/live/longpoll?token="+resp.Token, "application/x-ndjson", `[]`)
+	assert.Equal(t, http.StatusGone, resp.Status)
+}
+
+func TestLongPollNoExpiryDuringPoll(t *testing.T) {
+	tr := New("/live/longpoll",
+		WithIdleTimeout(20*time.Millisecond),
+		WithPollTimeout(100*time.Millisecond),
+	)
+
+	resp := do(t, tr, echo, http.MethodGet, "/live/longpoll", "", "")
+	token := resp.Token
+
+	resp = do(t, tr, echo, http.MethodGet, "/live/longpoll?token="+token, "", "")
+	assert.Equal(t, http.StatusNoContent, resp.Status)
+
+	resp = do(t, tr, echo, http.MethodPost, "/live/longpoll?token="+token, "application/x-ndjson", `[]`)
+	assert.Equal(t, http.StatusOK, resp.Status)
+}
+
+// failingWriter fails like a client that went away.
+type failingWriter struct {
+	httptest.ResponseRecorder
+}
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestLongPollRequeue(t *testing.T) {
+	tr := New("/live/longpoll", WithPollTimeout(time.Second))
+
+	handle := func(c channel.Conn) {
+		c.WriteMessage([]byte(`["1","1","lv:1","phx_reply",{}]`))
+		echo(c)
+	}
+
+	resp := do(t, tr, handle, http.MethodGet, "/live/longpoll", "", "")
+	token := resp.Token
+
+	// the poll waits for the message and fails to deliver it.
+	tr.Serve(handle, &failingWriter{}, httptest.NewRequest(http.MethodGet, "/live/longpoll?token="+token, nil))
+
+	resp = do(t, tr, handle, http.MethodGet, "/live/longpoll?token="+token, "", "")
+	assert.Equal(t, http.StatusOK, resp.Status)
+	assert.Equal(t, []string{`["1","1","lv:1","phx_reply",{}]`}, resp.Messages)
+}
+
+func TestSplitMessages(t *testing.T) {
+	tt := []struct {
+		name        string
+		contentType string
+		body        string
+		expected    []string
+	}{
+		{
+			name:        "single json message",
+			contentType: "application/json",
+			body:        `["1","1","lv:1","event",{}]`,
+			expected:    []string{`["1","1","lv:1","event",{}]`},
+		},
+		{
+			name:        "batched messages",
+			contentType: "application/x-ndjson",
+			body:        "[1]\n[2]\r\n",
+			expected:    []string{"[1]", "[2]"},
+		},
+		{
+			name:        "base64 binary message",
+			contentType: "application/x-ndjson",
+			body:        "AAECAw==",
+			expected:    []string{"\x00\x01\x02\x03"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			result := []string{}
+			for _, msg := range splitMessages(tc.contentType, []byte(tc.body)) {
+				result = append(result, string(msg))
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package longpoll
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-live-view/go-live-view/channel"
+)
+
+var _ channel.Conn = (*lpSession)(nil)
+
+var errSessionClosed = errors.New("longpoll session closed")
+
+// lpSession adapts a sequence of HTTP requests into a channel.Conn.
+type lpSession struct {
+	token       string
+	idleTimeout time.Duration
+	timer       *time.Timer
+
+	inbox  chan []byte
+	notify chan struct{}
+	closed chan struct{}
+	once   sync.Once
+
+	mu     sync.Mutex
+	outbox []string
+	// requests counts the requests in flight, the session does not
+	// expire during them.
+	requests int
+}
+
+func newSession(token string, idleTimeout time.Duration) *lpSession {
+	return &lpSession{
+		token:       token,
+		idleTimeout: idleTimeout,
+		inbox:       make(chan []byte, 64),
+		notify:      make(chan struct{}, 1),
+		closed:      make(chan struct{}),
+	}
+}
+
+func (s *lpSession) ReadMessage() ([]byte, error) {
+	select {
+	case msg := <-s.inbox:
+		return msg, nil
+	case <-s.closed:
+		return nil, io.EOF
+	}
+}
+
+func (s *lpSession) WriteMessage(data []byte) error {
+	select {
+	case <-s.closed:
+		return errSessionClosed
+	default:
+	}
+
+	s.mu.Lock()
+	s.outbox = append(s.outbox, string(data))
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// push hands a client message to the reader.
+func (s *lpSession) push(ctx context.Context, msg []byte) error {
+	select {
+	case s.inbox <- msg:
+		return nil
+	case <-s.closed:
+		return errSessionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wait blocks until messages are buffered, the timeout elapses or the
+// session is closed, and returns all buffered messages.
+func (s *lpSession) wait(ctx context.Context, timeout time.Duration) ([]string, error) {
+	if messages := s.drain(); len(messages) > 0 {
+		return messages, nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-s.notify:
+		return s.drain(), nil
+	case <-s.closed:
+		return nil, errSessionClosed
+	case <-timer.C:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, nil
+	}
+}
+
+func (s *lpSession) drain() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := s.outbox
+	s.outbox = nil
+
+	return messages
+}
+
+// requeue puts back messages a poll failed to deliver.
+func (s *lpSession) requeue(messages []string) {
+	s.mu.Lock()
+	s.outbox = append(messages, s.outbox...)
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// begin stops the idle expiry of the session until the request ends.
+func (s *lpSession) begin() {
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
+
+// end restarts the idle expiry once no request is in flight.
+func (s *lpSession) end() {
+	s.mu.Lock()
+	s.requests--
+	idle := s.requests == 0
+	s.mu.Unlock()
+
+	if idle && s.timer != nil {
+		s.timer.Reset(s.idleTimeout)
+	}
+}
+
+// busy reports whether a request is in flight.
+func (s *lpSession) busy() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests > 0
+}
+
+func (s *lpSession) close() {
+	s.once.Do(func() {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		close(s.closed)
+	})
+}
@@ -3,6 +3,8 @@ package websocket
 import (
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/go-live-view/go-live-view/channel"
 	"github.com/gobwas/ws"
@@ -12,13 +14,55 @@ import (
 
 var _ channel.Transport = (*wsTransport)(nil)
 
+type Option func(*wsTransport)
+
 type wsTransport struct {
-	path string
+	path        string
+	checkOrigin func(*http.Request) bool
+}
+
+// New returns a websocket transport served on path. By default only
+// connections from the origin the transport is served on are accepted.
+func New(path string, opts ...Option) *wsTransport {
+	t := &wsTransport{
+		path:        path,
+		checkOrigin: sameOrigin,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// WithAllowedOrigins accepts connections from the given origins, e.g.
+// "https://example.com". A leading "*." in the host matches any subdomain,
+// as in "https://*.example.com".
+func WithAllowedOrigins(origins ...string) Option {
+	return func(t *wsTransport) {
+		t.checkOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+
+			for _, allowed := range origins {
+				if matchOrigin(allowed, origin) {
+					return true
+				}
+			}
+
+			return false
+		}
+	}
 }
 
-func New(path string) *wsTransport {
-	return &wsTransport{
-		path: path,
+// WithCheckOrigin replaces the origin check. Returning false rejects the
+// connection with 403.
+func WithCheckOrigin(f func(*http.Request) bool) Option {
+	return func(t *wsTransport) {
+		t.checkOrigin = f
 	}
 }
 
@@ -28,6 +72,11 @@ func (t *wsTransport) Path() string {
 
 func (t *wsTransport) Serve(handle func(channel.Conn), w http.ResponseWriter, r *http.Request) {
 	if websocket.IsWebSocketUpgrade(r) {
+		if !t.checkOrigin(r) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
 		c, _, _, err := ws.UpgradeHTTP(r, w)
 		if err != nil {
 			return
@@ -38,6 +87,45 @@ func (t *wsTransport) Serve(handle func(channel.Conn), w http.ResponseWriter, r
 	}
 }
 
+// sameOrigin accepts requests whose origin host is the requested host.
+// Browsers always send an Origin header, requests without one come from
+// other clients and are accepted.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+func matchOrigin(allowed, origin string) bool {
+	a, err := url.Parse(allowed)
+	if err != nil {
+		return false
+	}
+
+	o, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	if a.Scheme != "" && !strings.EqualFold(a.Scheme, o.Scheme) {
+		return false
+	}
+
+	if suffix, ok := strings.CutPrefix(a.Host, "*."); ok {
+		return strings.HasSuffix(strings.ToLower(o.Host), "."+strings.ToLower(suffix))
+	}
+
+	return strings.EqualFold(a.Host, o.Host)
+}
+
 var _ channel.Conn = (*wsConn)(nil)
 
 type wsConn struct {
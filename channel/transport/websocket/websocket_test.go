@@ -0,0 +1,94 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-live-view/go-live-view/channel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	tt := []struct {
+		name     string
+		opts     []Option
+		origin   string
+		expected bool
+	}{
+		{
+			name:     "no origin",
+			origin:   "",
+			expected: true,
+		},
+		{
+			name:     "same origin",
+			origin:   "http://example.com",
+			expected: true,
+		},
+		{
+			name:     "cross origin",
+			origin:   "http://evil.com",
+			expected: false,
+		},
+		{
+			name:     "allowed origin",
+			opts:     []Option{WithAllowedOrigins("https://app.example.org")},
+			origin:   "https://app.example.org",
+			expected: true,
+		},
+		{
+			name:     "allowed origin with other scheme",
+			opts:     []Option{WithAllowedOrigins("https://app.example.org")},
+			origin:   "http://app.example.org",
+			expected: false,
+		},
+		{
+			name:     "allowed subdomain",
+			opts:     []Option{WithAllowedOrigins("https://*.example.org")},
+			origin:   "https://app.example.org",
+			expected: true,
+		},
+		{
+			name:     "allowed subdomain does not match parent",
+			opts:     []Option{WithAllowedOrigins("https://*.example.org")},
+			origin:   "https://example.org",
+			expected: false,
+		},
+		{
+			name:     "allowlist replaces same origin",
+			opts:     []Option{WithAllowedOrigins("https://app.example.org")},
+			origin:   "http://example.com",
+			expected: false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := New("/live/websocket", tc.opts...)
+
+			r := httptest.NewRequest(http.MethodGet, "http://example.com/live/websocket", nil)
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+
+			assert.Equal(t, tc.expected, tr.checkOrigin(r))
+		})
+	}
+}
+
+func TestServeRejectsOrigin(t *testing.T) {
+	tr := New("/live/websocket")
+
+	r := httptest.NewRequest(http.MethodGet, "http://example.com/live/websocket", nil)
+	r.Header.Set("Connection", "Upgrade")
+	r.Header.Set("Upgrade", "websocket")
+	r.Header.Set("Origin", "http://evil.com")
+	rec := httptest.NewRecorder()
+
+	tr.Serve(func(c channel.Conn) {
+		t.Fatal("connection was accepted")
+	}, rec, r)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
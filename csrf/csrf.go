@@ -0,0 +1,169 @@
+package csrf
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/rend"
+)
+
+const (
+	// CookieName is the cookie holding the per-session CSRF secret.
+	CookieName = "_lv_csrf"
+
+	// ParamName is the join param the client sends the token in.
+	ParamName = "_csrf_token"
+
+	secretSize = 32
+)
+
+var ErrInvalidToken = errors.New("invalid csrf token")
+
+type contextKey struct{}
+
+type tokenKey struct{}
+
+// Secret returns the CSRF secret of the browser session, creating it and
+// setting its cookie when missing.
+func Secret(w http.ResponseWriter, r *http.Request) (string, error) {
+	if secret := CookieSecret(r); secret != "" {
+		return secret, nil
+	}
+
+	secret, err := random(secretSize)
+	if err != nil {
+		return "", err
+	}
+
+	value := encode(secret)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return value, nil
+}
+
+// CookieSecret returns the secret of the cookie sent with r, or an empty
+// string if it is missing or invalid. Sockets read it when they connect
+// to verify the token of each join.
+func CookieSecret(r *http.Request) string {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		return ""
+	}
+
+	secret, err := decode(cookie.Value)
+	if err != nil || len(secret) != secretSize {
+		return ""
+	}
+
+	return cookie.Value
+}
+
+// Token returns a token for secret. Every call returns a different token
+// so the secret never appears in responses.
+func Token(secret string) (string, error) {
+	raw, err := decode(secret)
+	if err != nil {
+		return "", err
+	}
+
+	mask, err := random(len(raw))
+	if err != nil {
+		return "", err
+	}
+
+	return encode(append(mask, xor(mask, raw)...)), nil
+}
+
+// Verify reports whether token was created by Token for secret.
+func Verify(secret, token string) bool {
+	raw, err := decode(secret)
+	if err != nil || len(raw) != secretSize {
+		return false
+	}
+
+	masked, err := decode(token)
+	if err != nil || len(masked) != 2*len(raw) {
+		return false
+	}
+
+	mask, value := masked[:len(raw)], masked[len(raw):]
+
+	return subtle.ConstantTimeCompare(xor(mask, value), raw) == 1
+}
+
+// NewContext returns a copy of ctx carrying the token.
+func NewContext(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, contextKey{}, token)
+}
+
+// FromRequest returns the token of the current page, for example to embed
+// it in forms posted outside of LiveView.
+func FromRequest(r *http.Request) string {
+	token, _ := r.Context().Value(contextKey{}).(string)
+	return token
+}
+
+// SetToken stores the token of the page rendered with root for Meta.
+func SetToken(root *rend.Root, token string) {
+	root.SetValue(tokenKey{}, token)
+}
+
+// Meta renders the csrf-token meta tag the client reads the token from to
+// join. Put it in the head of the root layout.
+func Meta() rend.Node {
+	return &meta{}
+}
+
+type meta struct{}
+
+func (m *meta) Render(diff bool, root *rend.Root, t *rend.Rend, b *strings.Builder) error {
+	if root == nil {
+		return nil
+	}
+
+	token, _ := root.Value(tokenKey{}).(string)
+	if token == "" {
+		return nil
+	}
+
+	return html.Meta(
+		html.NameAttr("csrf-token"),
+		html.ContentAttr(token),
+	).Render(diff, root, t, b)
+}
+
+func xor(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+func random(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	return b, err
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,84 @@
+package csrf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecret(t *testing.T) {
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	secret, err := Secret(rec, r)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, secret)
+
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, CookieName, cookies[0].Name)
+
+	rec = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookies[0])
+
+	again, err := Secret(rec, r)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, again)
+	assert.Empty(t, rec.Result().Cookies())
+}
+
+func TestVerify(t *testing.T) {
+	secret, err := Secret(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NoError(t, err)
+
+	other, err := Secret(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NoError(t, err)
+
+	a, err := Token(secret)
+	assert.NoError(t, err)
+	b, err := Token(secret)
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.True(t, Verify(secret, a))
+	assert.True(t, Verify(secret, b))
+
+	assert.False(t, Verify(other, a))
+	assert.False(t, Verify(secret, ""))
+	assert.False(t, Verify(secret, "not-a-token"))
+	assert.False(t, Verify(secret, secret))
+}
+
+func TestVerifyEmptySecret(t *testing.T) {
+	assert.False(t, Verify("", ""))
+	assert.False(t, Verify("", "AAAA"))
+}
+
+func TestCookieSecret(t *testing.T) {
+	rec := httptest.NewRecorder()
+	secret, err := Secret(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, "/live/websocket", nil)
+	assert.Empty(t, CookieSecret(r))
+
+	r.AddCookie(rec.Result().Cookies()[0])
+	assert.Equal(t, secret, CookieSecret(r))
+
+	r = httptest.NewRequest(http.MethodGet, "/live/websocket", nil)
+	r.AddCookie(&http.Cookie{Name: CookieName, Value: "short"})
+	assert.Empty(t, CookieSecret(r))
+}
+
+func TestMeta(t *testing.T) {
+	root := rend.NewRoot()
+	assert.Equal(t, "", rend.RenderRootString(root, Meta()))
+
+	root = rend.NewRoot()
+	SetToken(root, "abc")
+	assert.Equal(t, `<meta name="csrf-token" content="abc"/>`, rend.RenderRootString(root, Meta()))
+}
@@ -0,0 +1,27 @@
+package dynamic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-live-view/go-live-view/rend"
+)
+
+// RawNode writes its value without escaping.
+type RawNode struct {
+	Value string
+}
+
+// Raw renders trusted markup as a dynamic. Never pass user input to Raw.
+func Raw(value string) rend.Node {
+	return Wrap(&RawNode{value})
+}
+
+func Rawf(format string, a ...any) rend.Node {
+	return Wrap(&RawNode{fmt.Sprintf(format, a...)})
+}
+
+func (raw *RawNode) Render(diff bool, root *rend.Root, t *rend.Rend, b *strings.Builder) error {
+	_, err := b.WriteString(raw.Value)
+	return err
+}
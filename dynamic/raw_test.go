@@ -0,0 +1,25 @@
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/testutils"
+)
+
+func TestRaw(t *testing.T) {
+	testCases := []testutils.TestCase{
+		{
+			Name:     "raw markup",
+			Node:     html.Div(Raw("<b>bold</b>")),
+			Expected: "<div><b>bold</b></div>",
+		},
+		{
+			Name:     "escaped text next to raw",
+			Node:     html.Div(Text("<b>"), Raw("<i>")),
+			Expected: "<div>&lt;b&gt;<i></div>",
+		},
+	}
+
+	testutils.RunTestCases(t, testCases, "raw")
+}
@@ -14,6 +14,7 @@ func TestText(t *testing.T) {
 		expected string
 	}{
 		{"static text", "hello", "hello"},
+		{"escaped text", `<b>"bold"</b>`, "&lt;b&gt;&#34;bold&#34;&lt;/b&gt;"},
 	}
 
 	for _, tc := range tt {
@@ -1,6 +1,7 @@
 package async
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-live-view/go-live-view/async"
@@ -16,14 +17,28 @@ type User struct {
 }
 
 type Live struct {
-	User *async.Async[*User]
+	User     *async.Async[*User]
+	LoadedAt string
 }
 
 func (l *Live) Mount(s lv.Socket, _ params.Params) error {
-	l.User = async.New(s, func() (*User, error) {
-		time.Sleep(2 * time.Second)
-		return &User{Name: "John"}, nil
-	})
+	l.User = async.Assign(s, "user", fetchUser)
+	return nil
+}
+
+func (l *Live) Event(s lv.Socket, event string, _ params.Params) error {
+	if event == "reload" {
+		// restarting under the same name cancels the running fetch.
+		l.User = async.Assign(s, "user", fetchUser)
+	}
+
+	return nil
+}
+
+func (l *Live) HandleAsync(_ lv.Socket, name string, result lv.AsyncResult) error {
+	if name == "user" && result.Err == nil {
+		l.LoadedAt = time.Now().Format(time.TimeOnly)
+	}
 
 	return nil
 }
@@ -31,18 +46,33 @@ func (l *Live) Mount(s lv.Socket, _ params.Params) error {
 func (l *Live) Render(_ rend.Node) (rend.Node, error) {
 	return html.Div(
 		html.H1(
-			dynamic.GoEmbed(func() rend.Node {
-				switch l.User.State() {
-				case async.Loading:
-					loadingMessage := "Loading..."
-					return dynamic.Text(loadingMessage)
-				case async.Failed:
-					err := l.User.Error().Error()
+			async.Render(l.User,
+				func() rend.Node {
+					return dynamic.Text("Loading...")
+				},
+				func(err error) rend.Node {
 					return dynamic.Textf("failed to load user: %s", err)
-				default:
-					return dynamic.Text(l.User.Value().Name)
-				}
-			}),
+				},
+				func(u *User) rend.Node {
+					return dynamic.Text(u.Name)
+				},
+			),
+		),
+		html.P(
+			dynamic.Textf("loaded at %s", l.LoadedAt),
+		),
+		html.Button(
+			html.Text("Reload"),
+			html.Attr("phx-click", "reload"),
 		),
 	), nil
 }
+
+func fetchUser(ctx context.Context) (*User, error) {
+	select {
+	case <-time.After(2 * time.Second):
+		return &User{Name: "John"}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -22,10 +22,8 @@ func New() *Live {
 
 func (l *Live) Mount(s lv.Socket, _ params.Params) error {
 	if s != nil {
-		go func() {
-			time.Sleep(1 * time.Second)
-			s.PushSelf("update", nil)
-		}()
+		// stopped automatically when the user leaves.
+		s.Interval(1*time.Second, "update")
 	}
 
 	l.Time = time.Now()
@@ -35,11 +33,6 @@ func (l *Live) Mount(s lv.Socket, _ params.Params) error {
 
 func (l *Live) Event(s lv.Socket, event string, _ params.Params) error {
 	if event == "update" {
-		go func() {
-			time.Sleep(1 * time.Second)
-			s.PushSelf("update", nil)
-		}()
-
 		l.Time = time.Now()
 	}
 
@@ -4,8 +4,6 @@ import (
 	"math/rand"
 	"time"
 
-	stdhtml "html"
-
 	"github.com/go-json-experiment/json"
 	"github.com/go-live-view/go-live-view/dynamic"
 	"github.com/go-live-view/go-live-view/html"
@@ -59,21 +57,13 @@ func NewOptions() Options {
 func (l *Live) Mount(s lv.Socket, _ params.Params) error {
 	l.Options = NewOptions()
 	if s != nil {
-		go func() {
-			time.Sleep(5 * time.Second)
-			s.PushSelf("update-chart", nil)
-		}()
+		s.Interval(5*time.Second, "update-chart")
 	}
 	return nil
 }
 
 func (l *Live) Event(s lv.Socket, event string, _ params.Params) error {
 	if event == "update-chart" {
-		go func() {
-			time.Sleep(5 * time.Second)
-			s.PushSelf("update-chart", nil)
-		}()
-
 		l.Options = NewOptions()
 	}
 
@@ -82,7 +72,7 @@ func (l *Live) Event(s lv.Socket, event string, _ params.Params) error {
 
 func (l *Live) Render(_ rend.Node) (rend.Node, error) {
 	b, _ := json.Marshal(l.Options, json.DefaultOptionsV2())
-	options := stdhtml.EscapeString(string(b))
+	options := string(b)
 
 	return html.Div(
 		html.Attrs(
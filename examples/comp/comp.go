@@ -3,7 +3,9 @@ package comp
 import (
 	"fmt"
 
+	"github.com/go-live-view/go-live-view/csrf"
 	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/phx"
 	"github.com/go-live-view/go-live-view/rend"
 )
 
@@ -22,6 +24,11 @@ func Unpkg(pkg, version string) rend.Node {
 func RootLayout(children ...rend.Node) rend.Node {
 	return html.Html(
 		html.Head(
+			csrf.Meta(),
+			phx.Title(
+				phx.TitleSuffix(" · go-live-view"),
+				phx.TitleSlot(html.Text("go-live-view")),
+			),
 			Unpkg("phoenix", "1.7.14"),
 			Unpkg("phoenix_live_view", "1.0.17"),
 			Unpkg("topbar", "2.0.2"),
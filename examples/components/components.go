@@ -0,0 +1,90 @@
+package components
+
+import (
+	"strconv"
+
+	"github.com/go-live-view/go-live-view/dynamic"
+	"github.com/go-live-view/go-live-view/html"
+	lv "github.com/go-live-view/go-live-view/liveview"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+)
+
+type Counter struct {
+	id    string
+	label string
+	count int
+}
+
+// Update receives the assigns of the parent and the ones sent with
+// SendUpdate, which only contain the count.
+func (c *Counter) Update(_ lv.Socket, p params.Params) error {
+	if _, ok := p["id"]; ok {
+		c.id = p.String("id")
+		c.label = p.String("label")
+	}
+
+	if _, ok := p["count"]; ok {
+		c.count = p.Int("count")
+	}
+
+	return nil
+}
+
+func (c *Counter) HandleEvent(_ lv.Socket, event string, _ params.Params) error {
+	switch event {
+	case "inc":
+		c.count++
+	case "dec":
+		c.count--
+	}
+
+	return nil
+}
+
+func (c *Counter) Render() (rend.Node, error) {
+	return html.Div(
+		html.IdAttr(c.id),
+		html.H2(
+			dynamic.Text(c.label),
+			html.Text(": "),
+			dynamic.Text(strconv.Itoa(c.count)),
+		),
+		html.Button(
+			html.Text("inc"),
+			html.Attr("phx-click", "inc"),
+			lv.Target(c.id),
+		),
+		html.Button(
+			html.Text("dec"),
+			html.Attr("phx-click", "dec"),
+			lv.Target(c.id),
+		),
+	), nil
+}
+
+type Live struct{}
+
+func (l *Live) Event(s lv.Socket, event string, _ params.Params) error {
+	if event == "reset" {
+		for _, id := range []string{"apples", "pears"} {
+			err := s.SendUpdate(id, params.Params{"count": 0})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *Live) Render(_ rend.Node) (rend.Node, error) {
+	return html.Div(
+		lv.LiveComponent("apples", &Counter{}, params.Params{"id": "apples", "label": "Apples"}),
+		lv.LiveComponent("pears", &Counter{}, params.Params{"id": "pears", "label": "Pears"}),
+		html.Button(
+			html.Text("reset"),
+			html.Attr("phx-click", "reset"),
+		),
+	), nil
+}
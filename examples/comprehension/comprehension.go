@@ -7,7 +7,6 @@ import (
 	"github.com/go-live-view/go-live-view/dynamic"
 	"github.com/go-live-view/go-live-view/html"
 	lv "github.com/go-live-view/go-live-view/liveview"
-	"github.com/go-live-view/go-live-view/params"
 	"github.com/go-live-view/go-live-view/rend"
 )
 
@@ -20,24 +19,33 @@ type Live struct {
 	users []*User
 }
 
-func (l *Live) Event(s lv.Socket, event string, p params.Params) error {
-	if event == "add-user" {
-		l.users = append(l.users,
-			&User{
-				ID:   len(l.users) + 1,
-				Name: fmt.Sprintf("User %d", len(l.users)+1),
-			},
-		)
+type deleteUser struct {
+	ID int `param:"id"`
+}
+
+func (l *Live) Events() []lv.EventBinding {
+	return []lv.EventBinding{
+		lv.On("add-user", l.addUser),
+		lv.On("delete-user", l.deleteUser),
 	}
+}
 
-	if event == "delete-user" {
-		id := p.Map("value").Int("id")
+func (l *Live) addUser(_ lv.Socket, _ struct{}) error {
+	l.users = append(l.users,
+		&User{
+			ID:   len(l.users) + 1,
+			Name: fmt.Sprintf("User %d", len(l.users)+1),
+		},
+	)
+
+	return nil
+}
 
-		for i, u := range l.users {
-			if u.ID == id {
-				l.users = append(l.users[:i], l.users[i+1:]...)
-				break
-			}
+func (l *Live) deleteUser(_ lv.Socket, in deleteUser) error {
+	for i, u := range l.users {
+		if u.ID == in.ID {
+			l.users = append(l.users[:i], l.users[i+1:]...)
+			break
 		}
 	}
 
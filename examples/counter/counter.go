@@ -27,6 +27,10 @@ func (l *Live) Event(s lv.Socket, event string, _ params.Params) error {
 	return nil
 }
 
+func (l *Live) PageTitle() string {
+	return "Counter " + strconv.Itoa(l.Count)
+}
+
 func (l *Live) Render(_ rend.Node) (rend.Node, error) {
 	return html.Div(
 		html.H1(
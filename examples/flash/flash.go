@@ -7,9 +7,7 @@ import (
 	"github.com/go-live-view/go-live-view/rend"
 )
 
-type Live struct {
-	flashes []string
-}
+type Live struct{}
 
 func (l *Live) Event(s lv.Socket, event string, p params.Params) error {
 	switch event {
@@ -19,7 +17,10 @@ func (l *Live) Event(s lv.Socket, event string, p params.Params) error {
 		s.PushNavigate("/", lv.WithFlash("info", "from navigate!"))
 	case "redirect":
 		s.Redirect("/", lv.WithFlash("info", "from redirect!"))
+	case "put":
+		s.PutFlash("info", "from put flash!")
 	case "no-flash-navigate":
+		s.ClearFlash()
 		s.PushNavigate("/")
 	}
 
@@ -46,6 +47,10 @@ func (l *Live) Render(_ rend.Node) (rend.Node, error) {
 			html.Attr("phx-value-key", "info"),
 			html.Attr("phx-value-msg", "from event"),
 		),
+		html.Button(
+			html.Text("put flash"),
+			html.Attr("phx-click", "put"),
+		),
 		html.Button(
 			html.Text("no flash navigate"),
 			html.Attr("phx-click", "no-flash-navigate"),
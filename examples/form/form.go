@@ -0,0 +1,95 @@
+package form
+
+import (
+	"github.com/go-live-view/go-live-view/dynamic"
+	"github.com/go-live-view/go-live-view/form"
+	"github.com/go-live-view/go-live-view/html"
+	lv "github.com/go-live-view/go-live-view/liveview"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/phx"
+	"github.com/go-live-view/go-live-view/rend"
+)
+
+type Signup struct {
+	Email   string `param:"email" validate:"required" pattern:"^[^@]+@[^@]+$"`
+	Name    string `param:"name" validate:"required,min=2"`
+	Age     int    `param:"age" validate:"min=18"`
+	Terms   bool   `param:"terms" validate:"required"`
+	Address struct {
+		City string `param:"city" validate:"required"`
+	} `param:"address"`
+}
+
+type Live struct {
+	form   *form.Form
+	signed *Signup
+}
+
+func (l *Live) Mount(_ lv.Socket, _ params.Params) error {
+	l.form = form.New("signup", &Signup{})
+	return nil
+}
+
+func (l *Live) Events() []lv.EventBinding {
+	return []lv.EventBinding{
+		lv.On("validate", l.validate),
+		lv.On("save", l.save),
+	}
+}
+
+func (l *Live) validate(_ lv.Socket, p params.Params) error {
+	l.form = form.Decode("signup", p, &Signup{})
+	return nil
+}
+
+func (l *Live) save(s lv.Socket, p params.Params) error {
+	in := &Signup{}
+
+	l.form = form.Decode("signup", p, in)
+	if !l.form.Valid() {
+		return nil
+	}
+
+	l.signed = in
+	s.PutFlash("info", "Welcome "+in.Name)
+
+	return nil
+}
+
+func (l *Live) Render(_ rend.Node) (rend.Node, error) {
+	address := l.form.Nested("address")
+
+	return html.Div(
+		phx.Form(l.form,
+			html.Attr("phx-change", "validate"),
+			html.Attr("phx-submit", "save"),
+			field("Email", l.form.Field("email"), html.TypeAttr("email")),
+			field("Name", l.form.Field("name")),
+			field("Age", l.form.Field("age"), html.TypeAttr("number")),
+			field("City", address.Field("city")),
+			html.Label(
+				phx.Checkbox(l.form.Field("terms")),
+				html.Text(" I accept the terms"),
+			),
+			phx.ErrorTag(l.form.Field("terms"), html.ClassAttr("error")),
+			html.Button(
+				html.Text("Sign up"),
+				html.TypeAttr("submit"),
+			),
+		),
+		dynamic.If(l.signed != nil, html.P(
+			html.Text("Signed up!"),
+		)),
+	), nil
+}
+
+func field(label string, f form.Field, attrs ...rend.Node) rend.Node {
+	return html.Div(
+		html.Label(
+			html.ForAttr(f.ID()),
+			html.Text(label),
+		),
+		phx.Input(f, attrs...),
+		phx.ErrorTag(f, html.ClassAttr("error")),
+	)
+}
@@ -2,6 +2,7 @@ package index
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/go-live-view/go-live-view/dynamic"
 	"github.com/go-live-view/go-live-view/html"
@@ -13,56 +14,39 @@ import (
 type Live struct {
 	Links []string
 
-	flashes []string
-}
-
-func (l *Live) fectchFlashes() []string {
-	flashes := l.flashes
-	l.flashes = []string{}
-	return flashes
+	flash lv.Flash
 }
 
 func (l *Live) Mount(s lv.Socket, p params.Params) error {
-	flash := p.Map("flash")
-	if flash != nil {
-		for k, v := range flash {
-			l.flashes = append(l.flashes, fmt.Sprintf("%s: %s", k, v))
-		}
+	if s != nil {
+		l.flash = s.Flash()
 	}
 
-	delete(p, "flash")
-
 	return nil
 }
 
-func (l *Live) Params(s lv.Socket, p params.Params) error {
-	flash := p.Map("flash")
-	if flash != nil {
-		for k, v := range flash {
-			l.flashes = append(l.flashes, fmt.Sprintf("%s: %s", k, v))
-		}
+func (l *Live) kinds() []string {
+	kinds := make([]string, 0, len(l.flash))
+	for kind := range l.flash {
+		kinds = append(kinds, kind)
 	}
+	sort.Strings(kinds)
 
-	return nil
-}
-
-func (l *Live) Event(_ lv.Socket, event string, p params.Params) error {
-	if event == "lv:flash" {
-		value := p.Map("value")
-		key := value.String("key")
-		msg := value.String("msg")
-		l.flashes = append(l.flashes, fmt.Sprintf("%s: %s", key, msg))
-	}
-	return nil
+	return kinds
 }
 
 func (i *Live) Render(child rend.Node) (rend.Node, error) {
 	return html.Div(
 		html.Div(
 			html.Ol(
-				dynamic.Range(i.fectchFlashes(), func(flash string) rend.Node {
+				dynamic.Range(i.kinds(), func(kind string) rend.Node {
 					return html.Li(
-						dynamic.Text(flash),
+						dynamic.Text(fmt.Sprintf("%s: %s", kind, i.flash.Get(kind))),
+						html.Button(
+							html.Text("x"),
+							html.Attr("phx-click", "lv:clear-flash"),
+							html.Attr("phx-value-key", kind),
+						),
 					)
 				}),
 			),
@@ -12,9 +12,11 @@ import (
 	"github.com/go-live-view/go-live-view/examples/broadcast"
 	"github.com/go-live-view/go-live-view/examples/charts"
 	"github.com/go-live-view/go-live-view/examples/comp"
+	"github.com/go-live-view/go-live-view/examples/components"
 	"github.com/go-live-view/go-live-view/examples/comprehension"
 	"github.com/go-live-view/go-live-view/examples/counter"
 	"github.com/go-live-view/go-live-view/examples/flash"
+	"github.com/go-live-view/go-live-view/examples/form"
 	"github.com/go-live-view/go-live-view/examples/index"
 	"github.com/go-live-view/go-live-view/examples/js"
 	"github.com/go-live-view/go-live-view/examples/nested"
@@ -25,6 +27,7 @@ import (
 	"github.com/go-live-view/go-live-view/handler"
 	lv "github.com/go-live-view/go-live-view/liveview"
 	"github.com/go-live-view/go-live-view/router"
+	"github.com/go-live-view/go-live-view/token"
 )
 
 const appJS = `
@@ -45,9 +48,11 @@ const appJS = `
 		}
 	}
 
+	const csrfToken = document.querySelector("meta[name='csrf-token']").getAttribute("content")
+
 	const lv = new LiveView.LiveSocket("/live", Phoenix.Socket, {
 		hooks: Hooks,
-		params: { liveview_version: "1.0.17" }
+		params: { liveview_version: "1.0.17", _csrf_token: csrfToken }
 	});
 
 	lv.connect();
@@ -67,6 +72,7 @@ func setupRoutes() lv.Router {
 			"/async",
 			"/broadcast",
 			"/chart",
+			"/components",
 			"/comprehension",
 			"/counter",
 			"/nested",
@@ -84,19 +90,21 @@ func setupRoutes() lv.Router {
 	root.Handle("/chart", &charts.Live{})
 	root.Handle("/async", &async.Live{})
 	root.Handle("/broadcast", broadcast.New())
+	root.Handle("/components", &components.Live{})
 	root.Handle("/comprehension", &comprehension.Live{})
 	root.Handle("/stream", &stream.Live{})
 	root.Handle("/scroll", &scroll.Live{})
 	root.Handle("/js", &js.Live{})
 	root.Handle("/flash", &flash.Live{})
+	root.Handle("/form", &form.Live{})
 
 	nest := root.Group("/nested", &nested.Live{})
 	nest.Handle("/:id", &nested.ShowLive{})
 	nest.Handle("/:id/edit", &nested.EditLive{})
 
 	snav := root.Group("/ssnav", &ssnav.Live{})
-	snav.Handle("/:id", &ssnav.ShowLive{})
-	snav.Handle("/:id/edit", &ssnav.EditLive{})
+	snav.Handle("/:id", &ssnav.ShowLive{}, router.WithName("ssnav.show"))
+	snav.Handle("/:id/edit", &ssnav.EditLive{}, router.WithName("ssnav.edit"))
 
 	return rt
 }
@@ -104,6 +112,21 @@ func setupRoutes() lv.Router {
 func main() {
 	ctx := context.Background()
 
+	secret := []byte(os.Getenv("SECRET_KEY"))
+	if len(secret) == 0 {
+		log.Println("SECRET_KEY not set, using a random key")
+		key, err := token.RandomKey()
+		if err != nil {
+			log.Fatal(err)
+		}
+		secret = key
+	}
+
+	tokenizer, err := token.New(secret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	mux := http.NewServeMux()
 
 	mux.Handle("/assets/app.js", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -114,7 +137,9 @@ func main() {
 		w.Write([]byte(""))
 	}))
 
-	mux.Handle("/", handler.NewHandler(ctx, setupRoutes))
+	mux.Handle("/", handler.NewHandler(ctx, setupRoutes,
+		handler.WithTokenizer(tokenizer),
+	))
 
 	srv := &http.Server{
 		Addr: "0.0.0.0:8080",
@@ -11,31 +11,22 @@ import (
 type Live struct {
 }
 
-func (u *Live) Event(s lv.Socket, e string, p params.Params) error {
-	if e == "navigate" {
-		return s.PushPatch(p.Map("value").String("href"))
-	}
-	return nil
-}
-
 func (u *Live) Render(child rend.Node) (rend.Node, error) {
 	return html.Div(
 		html.H1(
 			html.Text("Server Navigation"),
 		),
-		html.Button(
-			html.A(
-				html.Text("Show"),
-				html.Attr("phx-click", "navigate"),
-				html.Attr("phx-value-href", "/ssnav/1"),
-			),
+		html.A(
+			lv.Href("ssnav.show", params.Params{"id": 1}),
+			html.DataAttr("phx-link", "patch"),
+			html.DataAttr("phx-link-state", "push"),
+			html.Text("Show"),
 		),
-		html.Button(
-			html.A(
-				html.Text("Edit"),
-				html.Attr("phx-click", "navigate"),
-				html.Attr("phx-value-href", "/ssnav/1/edit"),
-			),
+		html.A(
+			lv.Href("ssnav.edit", params.Params{"id": 1}),
+			html.DataAttr("phx-link", "patch"),
+			html.DataAttr("phx-link-state", "push"),
+			html.Text("Edit"),
 		),
 		child,
 	), nil
@@ -0,0 +1,220 @@
+package form
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-live-view/go-live-view/params"
+)
+
+// unusedPrefix prefixes the keys the client sends for inputs the user has
+// not interacted with yet.
+const unusedPrefix = "_unused_"
+
+// Form binds a struct to the params of an HTML form. It provides the
+// name, id, value and errors of each field so that the inputs rendered
+// with the phx helpers decode back into the struct with params.Decode.
+type Form struct {
+	name   string
+	path   string
+	data   reflect.Value
+	params params.Params
+	errors params.Errors
+	err    error
+}
+
+// New returns a form named name showing the values of data, a struct or a
+// pointer to one. The name prefixes the input names, like user in
+// user[email]; an empty name leaves them unprefixed.
+func New(name string, data any) *Form {
+	return &Form{
+		name:   name,
+		data:   reflect.Indirect(reflect.ValueOf(data)),
+		params: params.Params{},
+		errors: params.Errors{},
+	}
+}
+
+// Decode decodes the fields of the form from p into data, a pointer to a
+// struct, and returns a form showing the submitted values and the errors
+// of the fields. p is the value of a phx-change or phx-submit event, e.g.
+// received with lv.On as params.Params.
+func Decode(name string, p params.Params, data any, opts ...params.DecodeOption) *Form {
+	f := New(name, data)
+
+	f.params = params.Expand(p)
+	if name != "" {
+		f.params = f.params.Map(name)
+	}
+
+	f.err = params.Decode(f.params, data, opts...)
+	errors.As(f.err, &f.errors)
+
+	return f
+}
+
+// Name returns the name of the form.
+func (f *Form) Name() string {
+	return f.name
+}
+
+// ID returns the DOM id of the form.
+func (f *Form) ID() string {
+	return id(f.name)
+}
+
+// Params returns the submitted params of the form.
+func (f *Form) Params() params.Params {
+	return f.params
+}
+
+// Valid returns true if the params decoded without errors.
+func (f *Form) Valid() bool {
+	return f.err == nil
+}
+
+// Err returns the error decoding the params, a params.Errors when fields
+// are invalid.
+func (f *Form) Err() error {
+	return f.err
+}
+
+// Field returns the field with the given param name.
+func (f *Form) Field(name string) Field {
+	return Field{
+		form: f,
+		name: name,
+	}
+}
+
+// Nested returns the form of the nested struct field with the given param
+// name. Its inputs are named like user[address][city].
+func (f *Form) Nested(name string) *Form {
+	return &Form{
+		name:   inputName(f.name, name),
+		path:   inputName(f.path, name),
+		data:   reflect.Indirect(fieldValue(f.data, name)),
+		params: f.params.Map(name),
+		errors: f.errors,
+		err:    f.err,
+	}
+}
+
+// Field is a field of a form.
+type Field struct {
+	form *Form
+	name string
+}
+
+// Name returns the name of the input, like user[email].
+func (fl Field) Name() string {
+	return inputName(fl.form.name, fl.name)
+}
+
+// ID returns the DOM id of the input, like user_email.
+func (fl Field) ID() string {
+	return id(fl.Name())
+}
+
+// Value returns the submitted value of the field, or else the value of the
+// struct field.
+func (fl Field) Value() string {
+	if raw, ok := fl.form.params[fl.name]; ok {
+		// repeated keys, like the hidden input of a checkbox, last one wins.
+		if values, ok := raw.([]any); ok && len(values) > 0 {
+			raw = values[len(values)-1]
+		}
+
+		return params.Params{"v": raw}.String("v")
+	}
+
+	v := reflect.Indirect(fieldValue(fl.form.data, fl.name))
+	if !v.IsValid() {
+		return ""
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// Used returns true once the user interacted with the input. The client
+// sends an _unused_ key for the inputs of a form that were not changed or
+// focused yet, and none when the form is submitted.
+func (fl Field) Used() bool {
+	return used(fl.form.params, fl.name)
+}
+
+// Errors returns the errors of the field once it is used, so that errors
+// don't show for the inputs the user did not reach yet.
+func (fl Field) Errors() []string {
+	if !fl.Used() {
+		return nil
+	}
+
+	return fl.AllErrors()
+}
+
+// AllErrors returns the errors of the field, used or not.
+func (fl Field) AllErrors() []string {
+	return fl.form.errors.Get(inputName(fl.form.path, fl.name))
+}
+
+func used(p params.Params, name string) bool {
+	value, ok := p[name]
+	if !ok {
+		return false
+	}
+
+	if nested, ok := value.(map[string]any); ok {
+		for key := range nested {
+			if !strings.HasPrefix(key, unusedPrefix) && used(nested, key) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	_, unused := p[unusedPrefix+name]
+
+	return !unused
+}
+
+// fieldValue returns the struct field read from the param name, following
+// the rules of params.Decode.
+func fieldValue(v reflect.Value, name string) reflect.Value {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("param")
+		if (ok && tag == name) || (!ok && strings.EqualFold(field.Name, name)) {
+			return v.Field(i)
+		}
+	}
+
+	return reflect.Value{}
+}
+
+func inputName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "[" + name + "]"
+}
+
+// id turns user[address][city] into user_address_city.
+func id(name string) string {
+	name = strings.ReplaceAll(name, "]", "")
+	return strings.ReplaceAll(name, "[", "_")
+}
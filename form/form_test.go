@@ -0,0 +1,93 @@
+package form
+
+import (
+	"testing"
+
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	City string `param:"city" validate:"required"`
+}
+
+type user struct {
+	Email   string  `param:"email" validate:"required"`
+	Name    string  `param:"name" validate:"required"`
+	Age     int     `param:"age"`
+	Admin   bool    `param:"admin"`
+	Address address `param:"address"`
+}
+
+func TestNew(t *testing.T) {
+	f := New("user", &user{Email: "john@example.com", Age: 42})
+
+	email := f.Field("email")
+	assert.Equal(t, "user[email]", email.Name())
+	assert.Equal(t, "user_email", email.ID())
+	assert.Equal(t, "john@example.com", email.Value())
+	assert.False(t, email.Used())
+	assert.Empty(t, email.Errors())
+
+	assert.Equal(t, "42", f.Field("age").Value())
+	assert.Equal(t, "false", f.Field("admin").Value())
+	assert.Equal(t, "", f.Field("unknown").Value())
+
+	city := f.Nested("address").Field("city")
+	assert.Equal(t, "user[address][city]", city.Name())
+	assert.Equal(t, "user_address_city", city.ID())
+	assert.True(t, f.Valid())
+}
+
+func TestDecode(t *testing.T) {
+	var u user
+
+	// name and the city were not touched yet.
+	f := Decode("user", params.Params{
+		"user[email]":                 "",
+		"user[name]":                  "",
+		"user[_unused_name]":          "",
+		"user[age]":                   "42",
+		"user[admin]":                 []any{"false", "true"},
+		"user[address][city]":         "",
+		"user[address][_unused_city]": "",
+	}, &u)
+
+	assert.False(t, f.Valid())
+	assert.Equal(t, 42, u.Age)
+	assert.True(t, u.Admin)
+	assert.Equal(t, "true", f.Field("admin").Value())
+
+	email := f.Field("email")
+	assert.True(t, email.Used())
+	assert.Equal(t, []string{"is required"}, email.Errors())
+
+	name := f.Field("name")
+	assert.False(t, name.Used())
+	assert.Empty(t, name.Errors())
+	assert.Equal(t, []string{"is required"}, name.AllErrors())
+
+	assert.False(t, f.Field("address").Used())
+
+	city := f.Nested("address").Field("city")
+	assert.False(t, city.Used())
+	assert.Equal(t, []string{"is required"}, city.AllErrors())
+}
+
+func TestDecodeSubmit(t *testing.T) {
+	var u user
+
+	f := Decode("user", params.Params{
+		"user": map[string]any{
+			"email":   "john@example.com",
+			"name":    "John",
+			"address": map[string]any{"city": "Paris"},
+		},
+	}, &u)
+
+	assert.True(t, f.Valid())
+	assert.NoError(t, f.Err())
+	assert.Equal(t, "Paris", u.Address.City)
+	assert.True(t, f.Field("address").Used())
+	assert.Equal(t, "Paris", f.Nested("address").Field("city").Value())
+}
@@ -2,15 +2,19 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 
 	"github.com/go-live-view/go-live-view/channel"
 	"github.com/go-live-view/go-live-view/channel/transport/longpoll"
 	"github.com/go-live-view/go-live-view/channel/transport/websocket"
+	"github.com/go-live-view/go-live-view/csrf"
 	"github.com/go-live-view/go-live-view/internal/lvchan"
 	"github.com/go-live-view/go-live-view/internal/lvuchan"
 	lv "github.com/go-live-view/go-live-view/liveview"
+	"github.com/go-live-view/go-live-view/session"
 )
 
 type handlerOption func(*handler)
@@ -23,28 +27,50 @@ type handler struct {
 	transports    []channel.Transport
 	tokenizer     tokenizer
 	sessionGetter sessionGetter
+	sessionStore  session.Store
+	origins       []string
+	noCSRF        bool
+	onError       channel.ErrorHandler
+	logger        *slog.Logger
+	filterParams  []lv.LifecycleOption
 }
 
 func NewHandler(ctx context.Context, setupRoutes func() lv.Router, opts ...handlerOption) *handler {
 	h := &handler{
-		ctx:         ctx,
-		setupRoutes: setupRoutes,
-		channelHub:  channel.NewHub(),
-		channels:    make(map[string]func() channel.Channel),
-		transports: []channel.Transport{
-			websocket.New("/live/websocket"),
-			longpoll.New("/live/longpoll"),
-		},
-		tokenizer:     &defaultTokenizer{},
+		ctx:           ctx,
+		setupRoutes:   setupRoutes,
+		channelHub:    channel.NewHub(),
+		channels:      make(map[string]func() channel.Channel),
 		sessionGetter: &defaultSessionGetter{},
+		logger:        slog.Default(),
 	}
 
-	go h.channelHub.Listen(h.ctx)
-
 	for _, opt := range opts {
 		opt(h)
 	}
 
+	if h.tokenizer == nil {
+		panic("handler: no tokenizer configured, use WithTokenizer or explicitly opt into WithInsecureTokenizer")
+	}
+
+	wsOpts := []websocket.Option{}
+	if len(h.origins) > 0 {
+		wsOpts = append(wsOpts, websocket.WithAllowedOrigins(h.origins...))
+	}
+
+	h.transports = append([]channel.Transport{
+		websocket.New("/live/websocket", wsOpts...),
+		longpoll.New("/live/longpoll"),
+	}, h.transports...)
+
+	go func() {
+		// without its pubsub the node misses the broadcasts of the others.
+		err := h.channelHub.Listen(h.ctx)
+		if err != nil {
+			h.logger.Error("pubsub error", "error", err)
+		}
+	}()
+
 	return h
 }
 
@@ -60,60 +86,182 @@ func WithTransport(transport channel.Transport) handlerOption {
 	}
 }
 
+// WithPubSub shares broadcasts with handlers on other nodes.
+func WithPubSub(ps channel.PubSub) handlerOption {
+	return func(h *handler) {
+		h.channelHub = channel.NewHub(channel.WithPubSub(ps))
+	}
+}
+
+// WithTokenizer sets the tokenizer used to sign session, static and
+// upload tokens, e.g. one created with token.New.
 func WithTokenizer(tokenizer tokenizer) handlerOption {
 	return func(h *handler) {
 		h.tokenizer = tokenizer
 	}
 }
 
+// WithInsecureTokenizer uses a tokenizer that only base64 encodes tokens.
+// Clients can forge them, only use it for development.
+func WithInsecureTokenizer() handlerOption {
+	return func(h *handler) {
+		h.tokenizer = &defaultTokenizer{}
+	}
+}
+
 func WithSessionGetter(getter sessionGetter) handlerOption {
 	return func(h *handler) {
 		h.sessionGetter = getter
 	}
 }
 
+// WithSessionStore loads the session of every HTTP request from store.
+// Views access it in HttpMount with session.FromRequest and its values are
+// merged into the params of Mount.
+func WithSessionStore(store session.Store) handlerOption {
+	return func(h *handler) {
+		h.sessionStore = store
+		h.sessionGetter = &session.Getter{Store: store}
+	}
+}
+
+// WithAllowedOrigins sets the origins the websocket transport accepts
+// connections from, see websocket.WithAllowedOrigins. Without it only the
+// origin the handler is served on is accepted.
+func WithAllowedOrigins(origins ...string) handlerOption {
+	return func(h *handler) {
+		h.origins = append(h.origins, origins...)
+	}
+}
+
+// WithoutCSRFProtection stops rendering the csrf-token meta and checking
+// the _csrf_token param on join.
+func WithoutCSRFProtection() handlerOption {
+	return func(h *handler) {
+		h.noCSRF = true
+	}
+}
+
+// WithErrorHandler reports the errors of the socket messages to f, e.g. to
+// send them to an error tracker. Errors of the HTTP render are reported
+// with a nil message. The client only gets a reason, not the error.
+func WithErrorHandler(f channel.ErrorHandler) handlerOption {
+	return func(h *handler) {
+		h.onError = f
+	}
+}
+
+// WithLogger sets the logger of the handler, its socket servers and
+// liveviews. Errors are logged at the error level and every message,
+// push, join, event and render at the debug level. It defaults to
+// slog.Default.
+func WithLogger(logger *slog.Logger) handlerOption {
+	return func(h *handler) {
+		h.logger = logger
+	}
+}
+
+// WithFilterParams adds keys to the keys of the params whose values are
+// not logged, e.g. card_number. Keys containing one of them are filtered
+// too. These are password, secret and token by default.
+func WithFilterParams(keys ...string) handlerOption {
+	return func(h *handler) {
+		h.filterParams = append(h.filterParams, lv.WithFilterParams(keys...))
+	}
+}
+
+// WithOnlyFilterParams replaces the keys of the params whose values are
+// not logged, without keys nothing is filtered.
+func WithOnlyFilterParams(keys ...string) handlerOption {
+	return func(h *handler) {
+		h.filterParams = append(h.filterParams, lv.WithOnlyFilterParams(keys...))
+	}
+}
+
+func (h *handler) lifecycleOptions() []lv.LifecycleOption {
+	opts := []lv.LifecycleOption{lv.WithLogger(h.logger)}
+	if h.noCSRF {
+		opts = append(opts, lv.WithoutCSRFProtection())
+	}
+
+	return append(opts, h.filterParams...)
+}
+
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	for _, transport := range h.transports {
 		if transport.Path() == r.URL.Path {
-			transport.Serve(h.handle, w, r)
+			// the joins of the connection are verified against the csrf
+			// secret of the browser that connected.
+			secret := csrf.CookieSecret(r)
+
+			transport.Serve(func(c channel.Conn) {
+				h.handle(c, secret)
+			}, w, r)
 			return
 		}
 	}
 
+	if h.sessionStore != nil {
+		r = session.Load(h.sessionStore, r)
+	}
+
 	resp, err := lv.NewLifecycle(
-		h.setupRoutes(), h.tokenizer, h.sessionGetter,
+		h.setupRoutes(), h.tokenizer, h.sessionGetter, h.lifecycleOptions()...,
 	).StaticRender(w, r)
 	if err != nil {
-		switch err.(type) {
-		case lv.HttpError:
-			httpErr := err.(lv.HttpError)
+		var httpErr lv.HttpError
+		if errors.As(err, &httpErr) {
 			w.WriteHeader(httpErr.Code())
 			w.Write([]byte(httpErr.Error()))
 			return
-		default:
-			w.WriteHeader(http.StatusInternalServerError)
-			fmt.Fprintf(w, "Error: %s", err.Error())
-			return
 		}
+
+		if h.onError != nil {
+			h.onError(nil, err)
+		} else {
+			h.logger.Error("liveview render error", "path", r.URL.Path, "error", err)
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "Error: %s", err.Error())
+		return
 	}
 
 	w.Write([]byte(resp))
 }
 
-func (h *handler) handle(t channel.Conn) {
-	server := channel.NewServer(t, h.channelHub)
+func (h *handler) handle(t channel.Conn, csrfSecret string) {
+	opts := []channel.ServerOption{channel.WithLogger(h.logger)}
+	if h.onError != nil {
+		opts = append(opts, channel.WithErrorHandler(h.onError))
+	}
+
+	server := channel.NewServer(t, h.channelHub, opts...)
 	h.channelHub.Add(server)
 	defer h.channelHub.Remove(server)
 
-	rt := h.setupRoutes()
-	lc := lv.NewLifecycle(rt, h.tokenizer, h.sessionGetter)
+	// the connection context is cancelled once the client disconnects.
+	ctx, cancel := context.WithCancel(h.ctx)
+	defer cancel()
+
+	lcOpts := append(h.lifecycleOptions(),
+		lv.WithContext(ctx),
+		lv.WithCSRFSecret(csrfSecret),
+		lv.WithNavigation(lv.NewNavigation()),
+	)
+
+	// every liveview joined gets its own lifecycle and mailbox, so a slow
+	// one does not hold up the others.
+	registry := lvchan.NewRegistry()
 
-	server.Route("lv:*", lvchan.New(lc))
-	server.Route("lvu:*", lvuchan.New(lc))
+	server.Route("lv:*", lvchan.New(func() lvchan.Lifecycle {
+		return lv.NewLifecycle(h.setupRoutes(), h.tokenizer, h.sessionGetter, lcOpts...)
+	}, lvchan.WithRegistry(registry), lvchan.WithLogger(h.logger)))
+	server.Route("lvu:*", lvuchan.New(registry.Upload))
 
 	for topic, factory := range h.channels {
 		server.Route(topic, factory)
 	}
 
-	server.Listen(h.ctx)
+	server.Listen(ctx)
 }
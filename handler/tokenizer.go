@@ -6,13 +6,14 @@ import (
 )
 
 type tokenizer interface {
-	Encode(any) (string, error)
-	Decode(string, any) error
+	Encode(string, any) (string, error)
+	Decode(string, string, any) error
 }
 
+// defaultTokenizer only encodes values, tokens can be forged by clients.
 type defaultTokenizer struct{}
 
-func (d *defaultTokenizer) Encode(v any) (string, error) {
+func (d *defaultTokenizer) Encode(_ string, v any) (string, error) {
 	b, err := json.Marshal(v)
 	if err != nil {
 		return "", err
@@ -21,7 +22,7 @@ func (d *defaultTokenizer) Encode(v any) (string, error) {
 	return base64.StdEncoding.EncodeToString(b), nil
 }
 
-func (d *defaultTokenizer) Decode(s string, v any) error {
+func (d *defaultTokenizer) Decode(_ string, s string, v any) error {
 	b, err := base64.StdEncoding.DecodeString(s)
 	if err != nil {
 		return err
@@ -2,6 +2,7 @@ package html
 
 import (
 	"fmt"
+	"html"
 	"strings"
 
 	"github.com/go-live-view/go-live-view/rend"
@@ -31,7 +32,7 @@ func (attr *AttributeNode) Render(diff bool, root *rend.Root, t *rend.Rend, b *s
 	}
 
 	for _, value := range attr.Values {
-		_, err = b.WriteString(value)
+		_, err = b.WriteString(html.EscapeString(value))
 		if err != nil {
 			return err
 		}
@@ -3,6 +3,7 @@ package html
 import (
 	"testing"
 
+	"github.com/go-live-view/go-live-view/js"
 	"github.com/go-live-view/go-live-view/testutils"
 )
 
@@ -31,6 +32,16 @@ func TestAttribute(t *testing.T) {
 			Node:     Div(Attr("attr")),
 			Expected: "<div attr></div>",
 		},
+		{
+			Name:     "escaped attribute value",
+			Node:     Div(Attr("title", `"><script>`)),
+			Expected: "<div title=\"&#34;&gt;&lt;script&gt;\"></div>",
+		},
+		{
+			Name:     "js commands are escaped once",
+			Node:     Div(Attr("phx-click", js.JS(js.Push("inc", nil)))),
+			Expected: "<div phx-click=\"[[&#34;push&#34;,{&#34;event&#34;:&#34;inc&#34;}]]\"></div>",
+		},
 	}
 
 	testutils.RunTestCases(t, tt, "attributes")
@@ -1,6 +1,7 @@
 package html
 
 import (
+	"html"
 	"strings"
 
 	"github.com/go-live-view/go-live-view/rend"
@@ -16,7 +17,7 @@ func Comment(s string) *CommentNode {
 
 func (c *CommentNode) Render(diff bool, root *rend.Root, t *rend.Rend, b *strings.Builder) error {
 	b.WriteString("<!--")
-	b.WriteString(c.Comment)
+	b.WriteString(html.EscapeString(c.Comment))
 	b.WriteString("-->")
 	return nil
 }
@@ -22,6 +22,11 @@ func TestComment(t *testing.T) {
 			),
 			Expected: "<div><!--Start of content-->Hello World<!--End of content--></div>",
 		},
+		{
+			Name:     "escaped comment",
+			Node:     Comment("--><script>"),
+			Expected: "<!----&gt;&lt;script&gt;-->",
+		},
 	}
 
 	testutils.RunTestCases(t, tt, "comment")
@@ -0,0 +1,26 @@
+package html
+
+import (
+	"testing"
+
+	"github.com/go-live-view/go-live-view/testutils"
+)
+
+func TestRaw(t *testing.T) {
+	tt := []testutils.TestCase{
+		{
+			Name:     "raw markup",
+			Node:     Raw("<b>bold</b>"),
+			Expected: "<b>bold</b>",
+		},
+		{
+			Name: "raw script in element",
+			Node: Script(
+				Raw(`console.log("a" < "b")`),
+			),
+			Expected: `<script>console.log("a" < "b")</script>`,
+		},
+	}
+
+	testutils.RunTestCases(t, tt, "raw")
+}
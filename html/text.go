@@ -2,6 +2,7 @@ package html
 
 import (
 	"fmt"
+	"html"
 	"strings"
 
 	"github.com/go-live-view/go-live-view/rend"
@@ -19,7 +20,8 @@ func Textf(format string, a ...any) *TextNode {
 	return &TextNode{fmt.Sprintf(format, a...)}
 }
 
+// Render writes the escaped text. Use Raw for trusted markup.
 func (txt *TextNode) Render(diff bool, root *rend.Root, t *rend.Rend, b *strings.Builder) error {
-	_, err := b.WriteString(txt.Value)
+	_, err := b.WriteString(html.EscapeString(txt.Value))
 	return err
 }
@@ -14,6 +14,8 @@ func TestText(t *testing.T) {
 		expected string
 	}{
 		{"static text", "hello", "hello"},
+		{"escaped text", "<script>alert('x')</script>", "&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt;"},
+		{"escaped ampersand", "a & b", "a &amp; b"},
 	}
 
 	for _, tc := range tt {
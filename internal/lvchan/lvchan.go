@@ -1,10 +1,16 @@
 package lvchan
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/go-live-view/go-live-view/channel"
+	"github.com/go-live-view/go-live-view/csrf"
+	"github.com/go-live-view/go-live-view/internal/mailbox"
 	lv "github.com/go-live-view/go-live-view/liveview"
 	"github.com/go-live-view/go-live-view/params"
 	"github.com/go-live-view/go-live-view/rend"
@@ -12,7 +18,9 @@ import (
 
 var _ channel.Channel = &lvChannel{}
 
-type lifecycle interface {
+// Lifecycle handles the callbacks of one liveview, see lv.NewLifecycle.
+type Lifecycle interface {
+	Begin(channel.Socket) (lv.Socket, context.CancelFunc)
 	Join(lv.Socket, params.Params) (*rend.Root, error)
 	Leave() error
 	StaticRender(http.ResponseWriter, *http.Request) (string, error)
@@ -21,25 +29,219 @@ type lifecycle interface {
 	AllowUpload(lv.Socket, params.Params) (any, error)
 	Progress(lv.Socket, params.Params) (*rend.Root, error)
 	DestroyCIDs([]int) error
+	UpdateComponent(lv.Socket, params.Params) (*rend.Root, error)
+	AsyncResult(lv.Socket, params.Params) (*rend.Root, error)
+	Timer(lv.Socket, params.Params) (*rend.Root, error)
+	Chunk(string, string, []byte, func() error) error
+	DecodeUploadToken(string) (string, string, error)
 }
 
+// lvChannel runs all callbacks of the liveview through its own mailbox so
+// they never run concurrently, while other liveviews of the connection
+// keep running. Client messages wait for their result, broadcasts and
+// self messages are queued.
 type lvChannel struct {
-	lc lifecycle
+	lc       Lifecycle
+	mb       *mailbox.Mailbox
+	logger   *slog.Logger
+	registry *Registry
 }
 
-func New(lc lifecycle) func() channel.Channel {
+type Option func(*lvChannel)
+
+// New returns channels running a new lifecycle each in their own mailbox.
+func New(newLifecycle func() Lifecycle, opts ...Option) func() channel.Channel {
 	return func() channel.Channel {
-		return &lvChannel{
-			lc: lc,
+		l := &lvChannel{
+			lc:     newLifecycle(),
+			mb:     mailbox.New(),
+			logger: slog.Default(),
+		}
+
+		for _, opt := range opts {
+			opt(l)
 		}
+
+		return l
+	}
+}
+
+// WithRegistry registers the joined liveviews in r for their uploads.
+func WithRegistry(r *Registry) Option {
+	return func(l *lvChannel) {
+		l.registry = r
+	}
+}
+
+// WithLogger sets the logger the queued broadcasts are logged with at the
+// debug level, client messages are logged by the channel server.
+func WithLogger(logger *slog.Logger) Option {
+	return func(l *lvChannel) {
+		l.logger = logger
 	}
 }
 
 func (l *lvChannel) Join(s channel.Socket, p any) error {
-	params := params.FromAny(p)
+	err := reply(l.mb.Call(func() error {
+		err := channel.Recover(func() error {
+			js := &joinSocket{Socket: s}
+
+			ls, done := l.lc.Begin(js)
+			defer done()
+
+			err := l.join(ls, p)
+
+			// the client follows redirects replied to the join.
+			if js.redirect != nil {
+				return &channel.ReplyError{Response: js.redirect}
+			}
+
+			return err
+		})
+		if err != nil {
+			// stop what the mount started, e.g. a timer before a
+			// redirect.
+			l.stop()
+		}
+
+		return err
+	}))
+	if err != nil {
+		// the server forgets channels failing to join.
+		l.close()
+		return err
+	}
+
+	if l.registry != nil {
+		l.registry.add(l)
+	}
+
+	return nil
+}
+
+// Leave stops the mailbox once the lifecycle left.
+func (l *lvChannel) Leave(s channel.Socket) error {
+	defer l.close()
+
+	return l.mb.Call(func() error {
+		return channel.Recover(func() error {
+			return l.leave(s)
+		})
+	})
+}
+
+func (l *lvChannel) close() {
+	if l.registry != nil {
+		l.registry.remove(l)
+	}
+
+	l.mb.Close()
+}
+
+func (l *lvChannel) Message(s channel.Socket, event string, p any) error {
+	err := reply(l.mb.Call(func() error {
+		return l.guard(func() error {
+			ls, done := l.lc.Begin(s)
+			defer done()
+
+			return l.message(ls, event, p)
+		})
+	}))
+	if crashed(err) {
+		l.close()
+	}
+
+	return err
+}
+
+// Broadcast queues the message. Its errors are reported with the socket
+// since there is no client message to reply to.
+func (l *lvChannel) Broadcast(s channel.Socket, event string, p any) error {
+	queued := time.Now()
+
+	return l.mb.Post(func() {
+		start := time.Now()
+
+		err := l.guard(func() error {
+			ls, done := l.lc.Begin(s)
+			defer done()
+
+			return l.broadcast(ls, event, p)
+		})
+		if err != nil {
+			s.ReportError(err)
+		}
+
+		// closing waits for this function to return.
+		if crashed(err) {
+			go l.close()
+		}
+
+		l.logger.Debug("liveview broadcast",
+			"event", event,
+			"queued", start.Sub(queued),
+			"duration", time.Since(start),
+		)
+	})
+}
+
+// guard runs f in the mailbox like channel.Recover. The server forgets
+// the channels that panic, so the lifecycle leaves to stop its timers and
+// async work before the client rejoins.
+func (l *lvChannel) guard(f func() error) error {
+	err := channel.Recover(f)
+	if crashed(err) {
+		l.stop()
+	}
 
-	rend, err := l.lc.Join(lv.NewSocket(s), params)
+	return err
+}
+
+// stop leaves the lifecycle of a channel the server forgets without a
+// phx_leave. It runs in the mailbox.
+func (l *lvChannel) stop() {
+	err := channel.Recover(l.lc.Leave)
 	if err != nil {
+		l.logger.Error("liveview leave", "error", err)
+	}
+}
+
+func crashed(err error) bool {
+	var panicErr *channel.PanicError
+	return errors.As(err, &panicErr)
+}
+
+// reply gives the errors the client reacts to their reason.
+func reply(err error) error {
+	switch {
+	case errors.Is(err, csrf.ErrInvalidToken), errors.Is(err, lv.ErrInvalidToken):
+		return channel.Reason(channel.ReasonUnauthorized, err)
+	}
+
+	return err
+}
+
+// joinSocket keeps the redirects of a join to reply them as errors, the
+// client ignores them in a successful join reply.
+type joinSocket struct {
+	channel.Socket
+	redirect map[string]any
+}
+
+func (s *joinSocket) Push(event string, payload any) error {
+	if event == "redirect" || event == "live_redirect" {
+		s.redirect = map[string]any{event: payload}
+		return nil
+	}
+
+	return s.Socket.Push(event, payload)
+}
+
+func (l *lvChannel) join(s lv.Socket, p any) error {
+	params := params.FromAny(p)
+
+	rend, err := l.lc.Join(s, params)
+	if err != nil || s.Redirected() {
 		return err
 	}
 
@@ -49,7 +251,7 @@ func (l *lvChannel) Join(s channel.Socket, p any) error {
 	})
 }
 
-func (l *lvChannel) Leave(s channel.Socket) error {
+func (l *lvChannel) leave(s channel.Socket) error {
 	err := l.lc.Leave()
 	if err != nil {
 		return err
@@ -58,7 +260,7 @@ func (l *lvChannel) Leave(s channel.Socket) error {
 	return s.Push("", nil)
 }
 
-func (l *lvChannel) Message(s channel.Socket, event string, p any) error {
+func (l *lvChannel) message(s lv.Socket, event string, p any) error {
 	params := params.FromAny(p)
 
 	switch event {
@@ -70,14 +272,16 @@ func (l *lvChannel) Message(s channel.Socket, event string, p any) error {
 		return l.handleAllowUploadEvent(s, params)
 	case "progress":
 		return l.handleProgressEvent(s, params)
-	case "cids_destroyed", "cids_will_destroy":
+	case "cids_will_destroy":
+		return s.Push("", nil)
+	case "cids_destroyed":
 		return l.handleDestroyCidsEvent(s, params)
 	default:
 		return fmt.Errorf("unhandled event: %s", event)
 	}
 }
 
-func (l *lvChannel) Broadcast(s channel.Socket, event string, p any) error {
+func (l *lvChannel) broadcast(s lv.Socket, event string, p any) error {
 	params := params.FromAny(p)
 
 	switch event {
@@ -85,20 +289,53 @@ func (l *lvChannel) Broadcast(s channel.Socket, event string, p any) error {
 		return l.handleEvent(s, params)
 	case "live_patch":
 		return l.handleLivePatchEvent(s, params)
+	case "send_update":
+		return l.handleSendUpdateEvent(s, params)
+	case "async_result":
+		return l.handleAsyncResultEvent(s, params)
+	case "timer":
+		return l.handleTimerEvent(s, params)
 	default:
 		return fmt.Errorf("unhandled event: %s", event)
 	}
 }
 
-func (l *lvChannel) handleEvent(s channel.Socket, p params.Params) error {
-	diff, err := l.lc.Event(lv.NewSocket(s), p)
+func (l *lvChannel) handleSendUpdateEvent(s lv.Socket, p params.Params) error {
+	diff, err := l.lc.UpdateComponent(s, p)
+	if err != nil {
+		return err
+	}
+
+	return s.Push("diff", diff)
+}
+
+func (l *lvChannel) handleAsyncResultEvent(s lv.Socket, p params.Params) error {
+	diff, err := l.lc.AsyncResult(s, p)
+	if err != nil || diff == nil {
+		return err
+	}
+
+	return s.Push("diff", diff)
+}
+
+func (l *lvChannel) handleTimerEvent(s lv.Socket, p params.Params) error {
+	diff, err := l.lc.Timer(s, p)
+	if err != nil || diff == nil {
+		return err
+	}
+
+	return s.Push("diff", diff)
+}
+
+func (l *lvChannel) handleEvent(s lv.Socket, p params.Params) error {
+	diff, err := l.lc.Event(s, p)
 	if err != nil {
 		return err
 	}
 	return s.Push("diff", diff)
 }
 
-func (l *lvChannel) handleDestroyCidsEvent(s channel.Socket, p params.Params) error {
+func (l *lvChannel) handleDestroyCidsEvent(s lv.Socket, p params.Params) error {
 	cids := p.IntSlice("cids")
 
 	err := l.lc.DestroyCIDs(cids)
@@ -109,8 +346,8 @@ func (l *lvChannel) handleDestroyCidsEvent(s channel.Socket, p params.Params) er
 	return s.Push("", cids)
 }
 
-func (l *lvChannel) handleLivePatchEvent(s channel.Socket, p params.Params) error {
-	diff, err := l.lc.Params(lv.NewSocket(s), p)
+func (l *lvChannel) handleLivePatchEvent(s lv.Socket, p params.Params) error {
+	diff, err := l.lc.Params(s, p)
 	if err != nil {
 		return err
 	}
@@ -118,8 +355,8 @@ func (l *lvChannel) handleLivePatchEvent(s channel.Socket, p params.Params) erro
 	return s.Push("diff", diff)
 }
 
-func (l *lvChannel) handleAllowUploadEvent(s channel.Socket, p params.Params) error {
-	payload, err := l.lc.AllowUpload(lv.NewSocket(s), p)
+func (l *lvChannel) handleAllowUploadEvent(s lv.Socket, p params.Params) error {
+	payload, err := l.lc.AllowUpload(s, p)
 	if err != nil {
 		return err
 	}
@@ -127,8 +364,8 @@ func (l *lvChannel) handleAllowUploadEvent(s channel.Socket, p params.Params) er
 	return s.Push("", payload)
 }
 
-func (l *lvChannel) handleProgressEvent(s channel.Socket, p params.Params) error {
-	payload, err := l.lc.Progress(lv.NewSocket(s), p)
+func (l *lvChannel) handleProgressEvent(s lv.Socket, p params.Params) error {
+	payload, err := l.lc.Progress(s, p)
 	if err != nil {
 		return err
 	}
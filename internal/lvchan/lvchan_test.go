@@ -0,0 +1,525 @@
+package lvchan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-live-view/go-live-view/channel"
+	"github.com/go-live-view/go-live-view/csrf"
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/internal/mailbox"
+	lv "github.com/go-live-view/go-live-view/liveview"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/go-live-view/go-live-view/router"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingLifecycle counts callbacks without synchronization, the race
+// detector fails the tests if two of them run at the same time.
+type countingLifecycle struct {
+	calls int
+}
+
+func (c *countingLifecycle) Begin(s channel.Socket) (lv.Socket, context.CancelFunc) {
+	return lv.NewSocket(s), func() {}
+}
+
+func (c *countingLifecycle) Join(lv.Socket, params.Params) (*rend.Root, error) {
+	c.calls++
+	return rend.NewRoot(), nil
+}
+
+func (c *countingLifecycle) Leave() error {
+	c.calls++
+	return nil
+}
+
+func (c *countingLifecycle) StaticRender(http.ResponseWriter, *http.Request) (string, error) {
+	return "", nil
+}
+
+func (c *countingLifecycle) Event(lv.Socket, params.Params) (*rend.Root, error) {
+	c.calls++
+	return nil, nil
+}
+
+func (c *countingLifecycle) Params(lv.Socket, params.Params) (*rend.Root, error) {
+	c.calls++
+	return nil, nil
+}
+
+func (c *countingLifecycle) AllowUpload(lv.Socket, params.Params) (any, error) {
+	c.calls++
+	return nil, nil
+}
+
+func (c *countingLifecycle) Progress(lv.Socket, params.Params) (*rend.Root, error) {
+	c.calls++
+	return nil, nil
+}
+
+func (c *countingLifecycle) DestroyCIDs([]int) error {
+	c.calls++
+	return nil
+}
+
+func (c *countingLifecycle) UpdateComponent(lv.Socket, params.Params) (*rend.Root, error) {
+	c.calls++
+	return nil, nil
+}
+
+func (c *countingLifecycle) AsyncResult(lv.Socket, params.Params) (*rend.Root, error) {
+	c.calls++
+	return nil, nil
+}
+
+func (c *countingLifecycle) Timer(lv.Socket, params.Params) (*rend.Root, error) {
+	c.calls++
+	return nil, nil
+}
+
+func (c *countingLifecycle) Chunk(string, string, []byte, func() error) error {
+	c.calls++
+	return nil
+}
+
+func (c *countingLifecycle) DecodeUploadToken(string) (string, string, error) {
+	return "", "", lv.ErrInvalidToken
+}
+
+type nopSocket struct {
+	channel.Socket
+}
+
+func (nopSocket) Push(string, any) error {
+	return nil
+}
+
+func TestCallbacksAreSerialized(t *testing.T) {
+	lc := &countingLifecycle{}
+
+	ch := New(func() Lifecycle { return lc })()
+	s := nopSocket{}
+
+	err := ch.Join(s, map[string]any{})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, ch.Broadcast(s, "event", map[string]any{}))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, ch.Message(s, "event", map[string]any{}))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, ch.Message(s, "live_patch", map[string]any{}))
+		}()
+	}
+	wg.Wait()
+
+	err = ch.Leave(s)
+	assert.NoError(t, err)
+
+	// broadcasts are queued, Leave returns once they ran
+	assert.Equal(t, 152, lc.calls)
+}
+
+// failingLifecycle fails its join and events with the given functions.
+type failingLifecycle struct {
+	countingLifecycle
+	join  func(lv.Socket) error
+	event func() error
+}
+
+func (f *failingLifecycle) Join(s lv.Socket, _ params.Params) (*rend.Root, error) {
+	err := f.join(s)
+	if err != nil || s.Redirected() {
+		return nil, err
+	}
+	return rend.NewRoot(), nil
+}
+
+func (f *failingLifecycle) Event(lv.Socket, params.Params) (*rend.Root, error) {
+	return nil, f.event()
+}
+
+type reportingSocket struct {
+	nopSocket
+	mu       sync.Mutex
+	reported []error
+	done     chan struct{}
+}
+
+func (s *reportingSocket) ReportError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reported = append(s.reported, err)
+	close(s.done)
+}
+
+func TestJoinErrors(t *testing.T) {
+	tt := []struct {
+		name     string
+		join     func(lv.Socket) error
+		response map[string]any
+	}{
+		{
+			name: "invalid csrf token",
+			join: func(lv.Socket) error {
+				return csrf.ErrInvalidToken
+			},
+			response: map[string]any{"reason": channel.ReasonUnauthorized},
+		},
+		{
+			name: "invalid session",
+			join: func(lv.Socket) error {
+				return fmt.Errorf("%w: session: expired", lv.ErrInvalidToken)
+			},
+			response: map[string]any{"reason": channel.ReasonUnauthorized},
+		},
+		{
+			name: "redirect",
+			join: func(s lv.Socket) error {
+				return s.Redirect("/login")
+			},
+			response: map[string]any{"redirect": map[string]any{"to": "/login"}},
+		},
+		{
+			name: "live redirect",
+			join: func(s lv.Socket) error {
+				return s.PushNavigate("/admin")
+			},
+			response: map[string]any{"live_redirect": map[string]any{"to": "/admin", "kind": "push"}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ch := New(func() Lifecycle { return &failingLifecycle{join: tc.join} })()
+
+			err := ch.Join(nopSocket{}, map[string]any{})
+
+			var reply *channel.ReplyError
+			assert.ErrorAs(t, err, &reply)
+			assert.Equal(t, tc.response, reply.Response)
+		})
+	}
+}
+
+func TestPanics(t *testing.T) {
+	newLifecycle := func() *failingLifecycle {
+		return &failingLifecycle{
+			join: func(lv.Socket) error { return nil },
+			event: func() error {
+				panic("boom")
+			},
+		}
+	}
+
+	t.Run("message", func(t *testing.T) {
+		lc := newLifecycle()
+		ch := New(func() Lifecycle { return lc })()
+
+		err := ch.Message(nopSocket{}, "event", map[string]any{})
+
+		var panicErr *channel.PanicError
+		assert.ErrorAs(t, err, &panicErr)
+
+		// the lifecycle left and the channel is closed.
+		assert.Equal(t, 1, lc.calls)
+
+		err = ch.Message(nopSocket{}, "event", map[string]any{})
+		assert.ErrorIs(t, err, mailbox.ErrClosed)
+	})
+
+	t.Run("broadcast", func(t *testing.T) {
+		lc := newLifecycle()
+		ch := New(func() Lifecycle { return lc })()
+
+		// broadcasts report their panics to the socket.
+		s := &reportingSocket{done: make(chan struct{})}
+
+		err := ch.Broadcast(s, "event", map[string]any{})
+		assert.NoError(t, err)
+
+		<-s.done
+		assert.Len(t, s.reported, 1)
+
+		var panicErr *channel.PanicError
+		assert.ErrorAs(t, s.reported[0], &panicErr)
+		assert.Equal(t, 1, lc.calls)
+
+		assert.Eventually(t, func() bool {
+			return ch.Broadcast(s, "event", map[string]any{}) == mailbox.ErrClosed
+		}, time.Second, time.Millisecond)
+	})
+}
+
+// blockingLifecycle handles events once unblocked.
+type blockingLifecycle struct {
+	countingLifecycle
+	unblock chan struct{}
+}
+
+func (b *blockingLifecycle) Event(lv.Socket, params.Params) (*rend.Root, error) {
+	<-b.unblock
+	return nil, nil
+}
+
+func TestLiveViewsRunIndependently(t *testing.T) {
+	slow := &blockingLifecycle{unblock: make(chan struct{})}
+	lifecycles := []Lifecycle{slow, &countingLifecycle{}}
+
+	factory := New(func() Lifecycle {
+		lc := lifecycles[0]
+		lifecycles = lifecycles[1:]
+		return lc
+	})
+
+	a, b := factory(), factory()
+	assert.NoError(t, a.Join(nopSocket{}, map[string]any{}))
+	assert.NoError(t, b.Join(nopSocket{}, map[string]any{}))
+
+	done := make(chan error)
+	go func() {
+		done <- a.Message(nopSocket{}, "event", map[string]any{})
+	}()
+
+	// the slow liveview does not hold up the other one.
+	assert.NoError(t, b.Message(nopSocket{}, "event", map[string]any{}))
+
+	close(slow.unblock)
+	assert.NoError(t, <-done)
+
+	assert.NoError(t, a.Leave(nopSocket{}))
+	assert.NoError(t, b.Leave(nopSocket{}))
+}
+
+// uploadLifecycle issued the upload token.
+type uploadLifecycle struct {
+	countingLifecycle
+	token string
+}
+
+func (u *uploadLifecycle) DecodeUploadToken(token string) (string, string, error) {
+	if token != u.token {
+		return "", "", lv.ErrInvalidToken
+	}
+	return "config", "entry", nil
+}
+
+func TestRegistryUpload(t *testing.T) {
+	registry := NewRegistry()
+
+	lcA := &uploadLifecycle{token: "a"}
+	lcB := &uploadLifecycle{token: "b"}
+	lifecycles := []Lifecycle{lcA, lcB}
+
+	factory := New(func() Lifecycle {
+		lc := lifecycles[0]
+		lifecycles = lifecycles[1:]
+		return lc
+	}, WithRegistry(registry))
+
+	a, b := factory(), factory()
+	assert.NoError(t, a.Join(nopSocket{}, map[string]any{}))
+	assert.NoError(t, b.Join(nopSocket{}, map[string]any{}))
+
+	target, err := registry.Upload("b")
+	assert.NoError(t, err)
+	assert.Equal(t, lcB, target.Lifecycle)
+	assert.Equal(t, "config", target.ConfigRef)
+	assert.Equal(t, "entry", target.Ref)
+
+	_, err = registry.Upload("forged")
+	assert.ErrorIs(t, err, lv.ErrInvalidToken)
+
+	assert.NoError(t, b.Leave(nopSocket{}))
+
+	_, err = registry.Upload("b")
+	assert.ErrorIs(t, err, lv.ErrInvalidToken)
+
+	assert.NoError(t, a.Leave(nopSocket{}))
+}
+
+// tickView counts the ticks of its interval and panics on boom.
+type tickView struct {
+	every time.Duration
+	ticks atomic.Int64
+}
+
+func (v *tickView) Mount(s lv.Socket, _ params.Params) error {
+	s.Interval(v.every, "tick")
+	return nil
+}
+
+func (v *tickView) Event(_ lv.Socket, event string, _ params.Params) error {
+	if event == "boom" {
+		panic("boom")
+	}
+
+	v.ticks.Add(1)
+
+	return nil
+}
+
+func (v *tickView) Render(rend.Node) (rend.Node, error) {
+	return html.Div(), nil
+}
+
+// jsonTokenizer encodes tokens as plain JSON.
+type jsonTokenizer struct{}
+
+func (jsonTokenizer) Encode(salt string, v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func (jsonTokenizer) Decode(salt, token string, v any) error {
+	return json.Unmarshal([]byte(token), v)
+}
+
+// selfSocket sends PushSelf to the channel joined last, like the server
+// does for the topic.
+type selfSocket struct {
+	nopSocket
+	mu sync.Mutex
+	ch channel.Channel
+}
+
+func (s *selfSocket) join(ch channel.Channel) error {
+	s.mu.Lock()
+	s.ch = ch
+	s.mu.Unlock()
+
+	return ch.Join(s, map[string]any{"url": "/", "session": `{"v":{}}`})
+}
+
+func (s *selfSocket) PushSelf(event string, payload any) error {
+	s.mu.Lock()
+	ch := s.ch
+	s.mu.Unlock()
+
+	return ch.Broadcast(s, event, payload)
+}
+
+func (s *selfSocket) ReportError(error) {}
+
+func TestCrashStopsTimers(t *testing.T) {
+	views := []*tickView{
+		{every: time.Millisecond},
+		{every: time.Hour},
+	}
+
+	joins := 0
+	newChannel := New(func() Lifecycle {
+		rt := router.NewRouter(func(children ...rend.Node) rend.Node {
+			return html.Body(children...)
+		})
+		rt.Handle("/", views[joins])
+		joins++
+
+		return lv.NewLifecycle(rt, jsonTokenizer{}, nil, lv.WithoutCSRFProtection())
+	})
+
+	s := &selfSocket{}
+
+	err := s.join(newChannel())
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return views[0].ticks.Load() > 0
+	}, time.Second, time.Millisecond)
+
+	err = s.ch.Message(s, "event", map[string]any{"event": "boom"})
+
+	var panicErr *channel.PanicError
+	assert.ErrorAs(t, err, &panicErr)
+
+	// the client rejoins the topic, where the ticks of the old interval
+	// would arrive and fire the timer of the new view.
+	err = s.join(newChannel())
+	assert.NoError(t, err)
+
+	ticks := views[0].ticks.Load()
+	time.Sleep(20 * time.Millisecond)
+
+	assert.Equal(t, ticks, views[0].ticks.Load())
+	assert.Zero(t, views[1].ticks.Load())
+}
+
+// failingMountView starts an interval on mount, then fails the join.
+type failingMountView struct {
+	redirect bool
+}
+
+func (v *failingMountView) Mount(s lv.Socket, _ params.Params) error {
+	s.Interval(time.Millisecond, "tick")
+
+	if v.redirect {
+		return s.Redirect("/login")
+	}
+
+	return nil
+}
+
+func (v *failingMountView) Render(rend.Node) (rend.Node, error) {
+	return nil, errors.New("render failed")
+}
+
+// tickSocket counts the ticks sent to the channel.
+type tickSocket struct {
+	nopSocket
+	ticks atomic.Int64
+}
+
+func (s *tickSocket) PushSelf(string, any) error {
+	s.ticks.Add(1)
+	return nil
+}
+
+func TestFailedJoinStopsTimers(t *testing.T) {
+	tt := []struct {
+		name string
+		view *failingMountView
+	}{
+		{name: "render error", view: &failingMountView{}},
+		{name: "redirect", view: &failingMountView{redirect: true}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := router.NewRouter(func(children ...rend.Node) rend.Node {
+				return html.Body(children...)
+			})
+			rt.Handle("/", tc.view)
+
+			ch := New(func() Lifecycle {
+				return lv.NewLifecycle(rt, jsonTokenizer{}, nil, lv.WithoutCSRFProtection())
+			})()
+
+			s := &tickSocket{}
+
+			err := ch.Join(s, map[string]any{"url": "/", "session": `{"v":{}}`})
+			assert.Error(t, err)
+
+			ticks := s.ticks.Load()
+			time.Sleep(10 * time.Millisecond)
+			assert.Equal(t, ticks, s.ticks.Load())
+		})
+	}
+}
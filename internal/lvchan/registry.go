@@ -0,0 +1,58 @@
+package lvchan
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-live-view/go-live-view/internal/lvuchan"
+	lv "github.com/go-live-view/go-live-view/liveview"
+)
+
+// Registry tracks the liveviews joined on a connection so upload channels
+// write to the liveview that allowed their upload.
+type Registry struct {
+	mu       sync.Mutex
+	channels map[*lvChannel]struct{}
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		channels: make(map[*lvChannel]struct{}),
+	}
+}
+
+// Upload returns the liveview that issued token.
+func (r *Registry) Upload(token string) (*lvuchan.Target, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for l := range r.channels {
+		configRef, ref, err := l.lc.DecodeUploadToken(token)
+		if err != nil {
+			continue
+		}
+
+		return &lvuchan.Target{
+			Lifecycle: l.lc,
+			Mailbox:   l.mb,
+			ConfigRef: configRef,
+			Ref:       ref,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: upload: no liveview issued it", lv.ErrInvalidToken)
+}
+
+func (r *Registry) add(l *lvChannel) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.channels[l] = struct{}{}
+}
+
+func (r *Registry) remove(l *lvChannel) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.channels, l)
+}
@@ -2,40 +2,71 @@ package lvuchan
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/go-live-view/go-live-view/channel"
+	"github.com/go-live-view/go-live-view/internal/mailbox"
 	"github.com/go-live-view/go-live-view/params"
 )
 
 var _ channel.Channel = &lvuChannel{}
 
-type lvuLifecycle interface {
+// Lifecycle writes the chunks of an upload.
+type Lifecycle interface {
 	Chunk(string, string, []byte, func() error) error
 }
 
+// Target is the liveview an upload writes its chunks to, with the mailbox
+// its callbacks run in.
+type Target struct {
+	Lifecycle Lifecycle
+	Mailbox   *mailbox.Mailbox
+	ConfigRef string
+	Ref       string
+}
+
+// lvuChannel uses the mailbox of the liveview that allowed the upload so
+// chunks are written while no other callback of the liveview runs.
 type lvuChannel struct {
-	lc        lvuLifecycle
-	configRef string
-	ref       string
+	find   func(token string) (*Target, error)
+	target *Target
 }
 
-func New(lc lvuLifecycle) func() channel.Channel {
+// New returns channels finding the liveview of their upload token with
+// find.
+func New(find func(token string) (*Target, error)) func() channel.Channel {
 	return func() channel.Channel {
 		return &lvuChannel{
-			lc: lc,
+			find: find,
 		}
 	}
 }
 
 func (l *lvuChannel) Join(s channel.Socket, p any) error {
+	return channel.Recover(func() error {
+		return l.join(s, p)
+	})
+}
+
+func (l *lvuChannel) Message(s channel.Socket, event string, p any) error {
+	if l.target == nil {
+		return fmt.Errorf("upload not joined")
+	}
+
+	return l.target.Mailbox.Call(func() error {
+		return channel.Recover(func() error {
+			return l.message(s, event, p)
+		})
+	})
+}
+
+func (l *lvuChannel) join(s channel.Socket, p any) error {
 	token := params.FromAny(p).String("token")
-	splits := strings.Split(token, "-")
-	if len(splits) != 2 {
-		return fmt.Errorf("invalid token")
+
+	target, err := l.find(token)
+	if err != nil {
+		return channel.Reason(channel.ReasonUnauthorized, err)
 	}
-	l.configRef = splits[0]
-	l.ref = splits[1]
+	l.target = target
 
 	return s.Push("", nil)
 }
@@ -48,14 +79,14 @@ func (l *lvuChannel) Broadcast(s channel.Socket, event string, p any) error {
 	return s.Push("", nil)
 }
 
-func (l *lvuChannel) Message(s channel.Socket, event string, p any) error {
+func (l *lvuChannel) message(s channel.Socket, event string, p any) error {
 	if event == "chunk" {
 		data, ok := p.([]byte)
 		if !ok {
 			return fmt.Errorf("invalid chunk data")
 		}
 
-		err := l.lc.Chunk(l.configRef, l.ref, data, s.Close)
+		err := l.target.Lifecycle.Chunk(l.target.ConfigRef, l.target.Ref, data, s.Close)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,123 @@
+package mailbox
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrClosed = errors.New("mailbox closed")
+
+// Mailbox runs functions one at a time on its own goroutine, in the order
+// they were posted.
+type Mailbox struct {
+	mu     sync.Mutex
+	queue  []func()
+	closed bool
+
+	notify  chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func New() *Mailbox {
+	m := &Mailbox{
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go m.run()
+
+	return m
+}
+
+// Post queues f without waiting for it to run. It never blocks, so it is
+// safe to call from a function running in the mailbox.
+func (m *Mailbox) Post(f func()) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return ErrClosed
+	}
+	m.queue = append(m.queue, f)
+	m.mu.Unlock()
+
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Call queues f and waits for its result. Calling it from a function
+// running in the mailbox deadlocks.
+func (m *Mailbox) Call(f func() error) error {
+	result := make(chan error, 1)
+
+	err := m.Post(func() {
+		result <- f()
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-m.stopped:
+		return ErrClosed
+	}
+}
+
+// Close stops the mailbox once the running function returns. Queued
+// functions are dropped.
+func (m *Mailbox) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		<-m.stopped
+		return
+	}
+	m.closed = true
+	m.queue = nil
+	m.mu.Unlock()
+
+	close(m.done)
+	<-m.stopped
+}
+
+func (m *Mailbox) run() {
+	defer close(m.stopped)
+
+	for {
+		select {
+		case <-m.notify:
+		case <-m.done:
+			return
+		}
+
+		for {
+			f := m.pop()
+			if f == nil {
+				break
+			}
+
+			f()
+		}
+	}
+}
+
+func (m *Mailbox) pop() func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.queue) == 0 {
+		return nil
+	}
+
+	f := m.queue[0]
+	m.queue = m.queue[1:]
+
+	return f
+}
@@ -0,0 +1,93 @@
+package mailbox
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder(t *testing.T) {
+	m := New()
+	defer m.Close()
+
+	var got []int
+	for i := 0; i < 100; i++ {
+		err := m.Post(func() {
+			got = append(got, i)
+		})
+		assert.NoError(t, err)
+	}
+
+	err := m.Call(func() error { return nil })
+	assert.NoError(t, err)
+
+	expected := make([]int, 100)
+	for i := range expected {
+		expected[i] = i
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestSerializes(t *testing.T) {
+	m := New()
+	defer m.Close()
+
+	// count is not synchronized, the race detector fails the test if two
+	// functions run at the same time.
+	count := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			m.Post(func() { count++ })
+		}()
+		go func() {
+			defer wg.Done()
+			m.Call(func() error {
+				count++
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	m.Call(func() error { return nil })
+	assert.Equal(t, 100, count)
+}
+
+func TestCallResult(t *testing.T) {
+	m := New()
+	defer m.Close()
+
+	expected := errors.New("failed")
+
+	err := m.Call(func() error { return expected })
+	assert.Equal(t, expected, err)
+}
+
+func TestPostFromMailbox(t *testing.T) {
+	m := New()
+	defer m.Close()
+
+	done := make(chan struct{})
+
+	err := m.Call(func() error {
+		return m.Post(func() { close(done) })
+	})
+	assert.NoError(t, err)
+
+	<-done
+}
+
+func TestClose(t *testing.T) {
+	m := New()
+	m.Close()
+	m.Close()
+
+	assert.ErrorIs(t, m.Post(func() {}), ErrClosed)
+	assert.ErrorIs(t, m.Call(func() error { return nil }), ErrClosed)
+}
@@ -1,14 +1,54 @@
+// Package tree matches URL paths against route patterns.
+//
+// A pattern is made of segments separated by slashes:
+//
+//   - foo matches the segment foo
+//   - :id matches any non-empty segment and captures it as id
+//   - :id<int> only matches the segments the constraint matches, either a
+//     named type (int, uuid) or a regular expression, like :slug<[a-z-]+>
+//   - :page? makes a trailing param optional, /posts/:page? matches both
+//     /posts and /posts/2
+//   - *path, last, matches the rest of the path, /files/*path captures
+//     a/b.txt from /files/a/b.txt. A bare * captures it as *.
+//
+// At each segment static segments are tried first, then constrained
+// params in the order they were added, then plain params and last the
+// catch-all. The first route matching the whole path wins. When none
+// does, the deepest route matching a prefix of the path is returned, so
+// /foo/bar finds /foo when nothing handles /foo/bar.
 package tree
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 )
 
+type kind int
+
+const (
+	static kind = iota
+	param
+	catchAll
+)
+
+// constraints are the named types of constrained params.
+var constraints = map[string]string{
+	"int":  `-?[0-9]+`,
+	"uuid": `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
 type Node[T any] struct {
 	segment  string
+	kind     kind
+	name     string
+	pattern  *regexp.Regexp
 	children map[string]*Node[T]
+	params   []*Node[T]
+	catchAll *Node[T]
 	route    T
+	hasRoute bool
 	depth    int
 }
 
@@ -24,107 +64,311 @@ func (n *Node[T]) GetRoute() T {
 	return n.route
 }
 
+// AddRoute adds route at path. It fails if path is invalid or if another
+// route matches the same paths.
 func (n *Node[T]) AddRoute(path string, route T) error {
 	segments := strings.Split(path, "/")[1:]
 
-	inserted := false
+	required := len(segments)
+	for i, segment := range segments {
+		optional := strings.HasPrefix(segment, ":") && strings.HasSuffix(segment, "?")
+
+		switch {
+		case optional && required == len(segments):
+			required = i
+		case !optional && required != len(segments):
+			return fmt.Errorf("route %s: optional segment %s is not trailing", path, segments[required])
+		}
+
+		if strings.HasPrefix(segment, "*") && i != len(segments)-1 {
+			return fmt.Errorf("route %s: catch-all %s is not last", path, segment)
+		}
+	}
+
+	// an optional segment adds a route without it.
+	var nodes []*Node[T]
+	for i := required; i <= len(segments); i++ {
+		variant := segments[:i]
+		if len(variant) == 0 {
+			variant = []string{""}
+		}
+
+		node, err := n.insert(path, variant)
+		if err != nil {
+			return err
+		}
+
+		if node.hasRoute {
+			return fmt.Errorf("route %s already exists", path)
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	for _, node := range nodes {
+		node.route = route
+		node.hasRoute = true
+	}
 
+	return nil
+}
+
+func (n *Node[T]) insert(path string, segments []string) (*Node[T], error) {
 	current := n
 	for _, segment := range segments {
-		if child, ok := current.children[segment]; ok {
-			current = child
-			continue
+		child, err := newNode[T](segment, current.depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", path, err)
 		}
 
-		newNode := &Node[T]{
-			segment:  segment,
-			children: make(map[string]*Node[T]),
-			depth:    current.depth + 1,
+		current, err = current.child(child)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", path, err)
 		}
+	}
 
-		current.children[segment] = newNode
-		current = newNode
-		inserted = true
+	return current, nil
+}
+
+func newNode[T any](segment string, depth int) (*Node[T], error) {
+	node := &Node[T]{
+		segment:  segment,
+		children: make(map[string]*Node[T]),
+		depth:    depth,
 	}
 
-	if !inserted {
-		return fmt.Errorf("route %s already exists", path)
+	switch {
+	case strings.HasPrefix(segment, ":"):
+		node.kind = param
+		node.name = strings.TrimSuffix(segment[1:], "?")
+
+		name, constraint, ok := strings.Cut(node.name, "<")
+		if !ok {
+			break
+		}
+
+		if !strings.HasSuffix(constraint, ">") {
+			return nil, fmt.Errorf("unterminated constraint in %s", segment)
+		}
+
+		expr := strings.TrimSuffix(constraint, ">")
+		if named, ok := constraints[expr]; ok {
+			expr = named
+		}
+
+		pattern, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("constraint of %s: %w", segment, err)
+		}
+
+		node.name = name
+		node.pattern = pattern
+	case strings.HasPrefix(segment, "*"):
+		node.kind = catchAll
+		node.name = segment[1:]
+		if node.name == "" {
+			node.name = "*"
+		}
 	}
 
-	current.route = route
+	return node, nil
+}
 
-	return nil
+// child returns the child of n matching the same segments as node, adding
+// node if there is none.
+func (n *Node[T]) child(node *Node[T]) (*Node[T], error) {
+	switch node.kind {
+	case static:
+		if child, ok := n.children[node.segment]; ok {
+			return child, nil
+		}
+		n.children[node.segment] = node
+	case param:
+		for _, child := range n.params {
+			if child.constraint() != node.constraint() {
+				continue
+			}
+
+			if child.name != node.name {
+				return nil, fmt.Errorf("%s conflicts with %s", node.segment, child.segment)
+			}
+
+			return child, nil
+		}
+
+		n.params = append(n.params, node)
+
+		// constrained params are tried before plain ones.
+		for i := len(n.params) - 1; i > 0 && n.params[i-1].pattern == nil && node.pattern != nil; i-- {
+			n.params[i-1], n.params[i] = n.params[i], n.params[i-1]
+		}
+	case catchAll:
+		if n.catchAll != nil {
+			if n.catchAll.name != node.name {
+				return nil, fmt.Errorf("%s conflicts with %s", node.segment, n.catchAll.segment)
+			}
+
+			return n.catchAll, nil
+		}
+		n.catchAll = node
+	}
+
+	return node, nil
+}
+
+func (n *Node[T]) constraint() string {
+	if n.pattern == nil {
+		return ""
+	}
+
+	return n.pattern.String()
 }
 
+// FindNode returns the node of the route matching path and the params
+// captured along it, see the package documentation for the rules.
 func (n *Node[T]) FindNode(path string) (*Node[T], map[string]any, error) {
 	segments := strings.Split(path, "/")[1:]
 
-	currentFind := n
-	currentParams := make(map[string]any)
+	m := n.match(segments)
+	if m == nil {
+		return n, make(map[string]any), nil
+	}
 
-	current := n
-	for i, segment := range segments {
-		currentPath := strings.Join(segments[i:], "/")
+	return m.node, m.params, nil
+}
 
-		if child, ok := current.children[segment]; ok {
-			found, params, err := child.FindNode(currentPath)
-			if err != nil {
-				return nil, nil, err
-			}
+type match[T any] struct {
+	node   *Node[T]
+	params map[string]any
+	full   bool
+}
+
+// match matches segments against the children of n.
+func (n *Node[T]) match(segments []string) *match[T] {
+	if len(segments) == 0 {
+		if !n.hasRoute {
+			return nil
+		}
+
+		return &match[T]{node: n, params: make(map[string]any), full: true}
+	}
+
+	var best *match[T]
+	if n.hasRoute {
+		best = &match[T]{node: n, params: make(map[string]any)}
+	}
+
+	segment := segments[0]
+
+	try := func(m *match[T]) *match[T] {
+		if m == nil {
+			return nil
+		}
+
+		if m.full {
+			return m
+		}
+
+		if best == nil || m.node.depth > best.node.depth {
+			best = m
+		}
+
+		return nil
+	}
+
+	if child, ok := n.children[segment]; ok {
+		if m := try(child.match(segments[1:])); m != nil {
+			return m
+		}
+	}
+
+	for _, child := range n.params {
+		if segment == "" || (child.pattern != nil && !child.pattern.MatchString(segment)) {
+			continue
+		}
+
+		m := child.match(segments[1:])
+		if m != nil {
+			m.params[child.name] = segment
+		}
 
-			if found.depth > currentFind.depth {
-				currentParams = params
-				currentFind = found
+		if m := try(m); m != nil {
+			return m
+		}
+	}
+
+	if child := n.catchAll; child != nil && child.hasRoute {
+		rest := strings.Join(segments, "/")
+		if rest != "" {
+			return &match[T]{
+				node:   child,
+				params: map[string]any{child.name: rest},
+				full:   true,
 			}
 		}
+	}
 
-		for key, child := range current.children {
-			if strings.HasPrefix(key, ":") {
-				found, params, err := child.FindNode(currentPath)
-				if err != nil {
-					return nil, nil, err
-				}
+	return best
+}
+
+// Fill builds the path of pattern from values. It returns the names of the
+// values it used and fails if a param is missing or does not match its
+// constraint. Missing optional params are left out.
+func Fill(pattern string, values map[string]string) (string, []string, error) {
+	segments := strings.Split(pattern, "/")[1:]
+
+	var b strings.Builder
+	var used []string
+	var skipped string
 
-				if found.depth > currentFind.depth {
-					params[key[1:]] = segment
-					currentParams = params
-					currentFind = found
+	for _, segment := range segments {
+		node, err := newNode[struct{}](segment, 0)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if node.kind == static {
+			b.WriteString("/" + segment)
+			continue
+		}
+
+		value := values[node.name]
+		if value == "" {
+			if node.kind == param && strings.HasSuffix(segment, "?") {
+				if skipped == "" {
+					skipped = node.name
 				}
+				continue
 			}
 
-			if key == "*" {
-				found, params, err := child.FindNode(currentPath)
-				if err != nil {
-					return nil, nil, err
-				}
+			return "", nil, fmt.Errorf("missing param %s", node.name)
+		}
 
-				if found.depth > currentFind.depth {
-					params["*"] = segment
-					currentParams = params
-					currentFind = found
-				}
+		if skipped != "" {
+			return "", nil, fmt.Errorf("param %s is set without %s", node.name, skipped)
+		}
+
+		if node.pattern != nil && !node.pattern.MatchString(value) {
+			return "", nil, fmt.Errorf("param %s: %q does not match %s", node.name, value, segment)
+		}
+
+		used = append(used, node.name)
+
+		if node.kind == catchAll {
+			parts := strings.Split(value, "/")
+			for i := range parts {
+				parts[i] = url.PathEscape(parts[i])
 			}
-			// if strings.HasPrefix(key, "~r") {
-			// 	regex := key[2:]
-			// 	r, err := regexp.Compile(regex)
-			// 	if err != nil {
-			// 		return nil, nil, err
-			// 	}
-			// 	if r.MatchString(segment) {
-			// 		found, params, err := child.FindNode(currentPath)
-			// 		if err != nil {
-			// 			return nil, nil, err
-			// 		}
-
-			// 		if found.depth > currentFind.depth {
-			// 			params[key] = segment
-			// 			currentParams = params
-			// 			currentFind = found
-			// 		}
-			// 	}
-			// }
-		}
-	}
-
-	return currentFind, currentParams, nil
+			b.WriteString("/" + strings.Join(parts, "/"))
+			continue
+		}
+
+		b.WriteString("/" + url.PathEscape(value))
+	}
+
+	if b.Len() == 0 {
+		return "/", used, nil
+	}
+
+	return b.String(), used, nil
 }
@@ -75,6 +75,111 @@ func TestFindNode(t *testing.T) {
 				"*":  "bar",
 			},
 		},
+		{
+			name:         "unrelated deeper route",
+			paths:        []string{"/bar"},
+			search:       "/foo/bar",
+			expect:       "",
+			expectParams: map[string]any{},
+		},
+		{
+			name:         "partial match falls back to the deepest route",
+			paths:        []string{"/a", "/a/b/c"},
+			search:       "/a/b/x",
+			expect:       "/a",
+			expectParams: map[string]any{},
+		},
+		{
+			name:         "params don't match empty segments",
+			paths:        []string{"/users", "/users/:id"},
+			search:       "/users/",
+			expect:       "/users",
+			expectParams: map[string]any{},
+		},
+		{
+			name:         "typed param",
+			paths:        []string{"/posts/:id<int>", "/posts/:slug"},
+			search:       "/posts/42",
+			expect:       "/posts/:id<int>",
+			expectParams: map[string]any{"id": "42"},
+		},
+		{
+			name:         "typed param falls through",
+			paths:        []string{"/posts/:slug", "/posts/:id<int>"},
+			search:       "/posts/hello",
+			expect:       "/posts/:slug",
+			expectParams: map[string]any{"slug": "hello"},
+		},
+		{
+			name:         "uuid param",
+			paths:        []string{"/items/:id<uuid>"},
+			search:       "/items/123e4567-e89b-12d3-a456-426614174000",
+			expect:       "/items/:id<uuid>",
+			expectParams: map[string]any{"id": "123e4567-e89b-12d3-a456-426614174000"},
+		},
+		{
+			name:         "regex param",
+			paths:        []string{"/tags/:slug<[a-z-]+>"},
+			search:       "/tags/go-live",
+			expect:       "/tags/:slug<[a-z-]+>",
+			expectParams: map[string]any{"slug": "go-live"},
+		},
+		{
+			name:         "regex param is anchored",
+			paths:        []string{"/tags", "/tags/:slug<[a-z-]+>"},
+			search:       "/tags/Go",
+			expect:       "/tags",
+			expectParams: map[string]any{},
+		},
+		{
+			name:         "optional param missing",
+			paths:        []string{"/posts/:page?"},
+			search:       "/posts",
+			expect:       "/posts/:page?",
+			expectParams: map[string]any{},
+		},
+		{
+			name:         "optional param set",
+			paths:        []string{"/posts/:page?"},
+			search:       "/posts/2",
+			expect:       "/posts/:page?",
+			expectParams: map[string]any{"page": "2"},
+		},
+		{
+			name:         "optional params at the root",
+			paths:        []string{"/:lang?/:page<int>?"},
+			search:       "/",
+			expect:       "/:lang?/:page<int>?",
+			expectParams: map[string]any{},
+		},
+		{
+			name:         "catch-all captures the rest",
+			paths:        []string{"/files/*path"},
+			search:       "/files/a/b/c.txt",
+			expect:       "/files/*path",
+			expectParams: map[string]any{"path": "a/b/c.txt"},
+		},
+		{
+			name:         "catch-all needs a segment",
+			paths:        []string{"/files/*path"},
+			search:       "/files",
+			expect:       "",
+			expectParams: map[string]any{},
+		},
+		{
+			name:         "param before catch-all",
+			paths:        []string{"/files/*", "/files/:name"},
+			search:       "/files/a",
+			expect:       "/files/:name",
+			expectParams: map[string]any{"name": "a"},
+		},
+		{
+			name:         "catch-all after a partial param match",
+			paths:        []string{"/files/*", "/files/:name"},
+			search:       "/files/a/b",
+			expect:       "/files/*",
+			expectParams: map[string]any{"*": "a/b"},
+		},
 		{
 			name:   "duplicate route",
 			paths:  []string{"/foo", "/foo"},
@@ -89,7 +194,7 @@ func TestFindNode(t *testing.T) {
 			for _, path := range tc.paths {
 				err := tree.AddRoute(path, path)
 				if err != nil {
-					assert.Equal(t, tc.err, err)
+					assert.EqualError(t, err, tc.err.Error())
 					return
 				}
 			}
@@ -103,3 +208,172 @@ func TestFindNode(t *testing.T) {
 		})
 	}
 }
+
+func TestAddRoute(t *testing.T) {
+	tt := []struct {
+		name  string
+		paths []string
+		err   string
+	}{
+		{
+			name:  "shorter route after a longer one",
+			paths: []string{"/foo/bar", "/foo"},
+		},
+		{
+			name:  "params with different constraints",
+			paths: []string{"/posts/:id<int>", "/posts/:slug"},
+		},
+		{
+			name:  "param names conflict",
+			paths: []string{"/users/:id", "/users/:name/edit"},
+			err:   "route /users/:name/edit: :name conflicts with :id",
+		},
+		{
+			name:  "same constraint conflicts",
+			paths: []string{"/posts/:id<int>", "/posts/:n<-?[0-9]+>"},
+			err:   "route /posts/:n<-?[0-9]+>: :n<-?[0-9]+> conflicts with :id<int>",
+		},
+		{
+			name:  "catch-all names conflict",
+			paths: []string{"/files/*path", "/files/*rest"},
+			err:   "route /files/*rest: *rest conflicts with *path",
+		},
+		{
+			name:  "optional segment overlaps a route",
+			paths: []string{"/posts", "/posts/:page?"},
+			err:   "route /posts/:page? already exists",
+		},
+		{
+			name:  "optional segment not trailing",
+			paths: []string{"/:lang?/posts"},
+			err:   "route /:lang?/posts: optional segment :lang? is not trailing",
+		},
+		{
+			name:  "catch-all not last",
+			paths: []string{"/files/*path/edit"},
+			err:   "route /files/*path/edit: catch-all *path is not last",
+		},
+		{
+			name:  "invalid constraint",
+			paths: []string{"/tags/:slug<[a-z>"},
+			err:   "route /tags/:slug<[a-z>: constraint of :slug<[a-z>: error parsing regexp: missing closing ]: `[a-z)$`",
+		},
+		{
+			name:  "unterminated constraint",
+			paths: []string{"/tags/:slug<[a-z]"},
+			err:   "route /tags/:slug<[a-z]: unterminated constraint in :slug<[a-z]",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[string]()
+
+			var err error
+			for _, path := range tc.paths {
+				err = tree.AddRoute(path, path)
+				if err != nil {
+					break
+				}
+			}
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestAddRouteConflictKeepsExisting(t *testing.T) {
+	tree := New[string]()
+
+	assert.NoError(t, tree.AddRoute("/posts", "/posts"))
+	assert.Error(t, tree.AddRoute("/posts/:page?", "/posts/:page?"))
+
+	// none of the paths of the failed route were added.
+	node, _, err := tree.FindNode("/posts/2")
+	assert.NoError(t, err)
+	assert.Equal(t, "/posts", node.GetRoute())
+}
+
+func TestFill(t *testing.T) {
+	tt := []struct {
+		name    string
+		pattern string
+		values  map[string]string
+		expect  string
+		used    []string
+		err     string
+	}{
+		{
+			name:    "root",
+			pattern: "/",
+			expect:  "/",
+		},
+		{
+			name:    "params",
+			pattern: "/users/:id/posts/:slug<[a-z-]+>",
+			values:  map[string]string{"id": "1", "slug": "hello-world", "page": "2"},
+			expect:  "/users/1/posts/hello-world",
+			used:    []string{"id", "slug"},
+		},
+		{
+			name:    "escaped param",
+			pattern: "/search/:q",
+			values:  map[string]string{"q": "a b/c"},
+			expect:  "/search/a%20b%2Fc",
+			used:    []string{"q"},
+		},
+		{
+			name:    "catch-all keeps slashes",
+			pattern: "/files/*path",
+			values:  map[string]string{"path": "a b/c.txt"},
+			expect:  "/files/a%20b/c.txt",
+			used:    []string{"path"},
+		},
+		{
+			name:    "missing optional param",
+			pattern: "/posts/:page<int>?",
+			expect:  "/posts",
+		},
+		{
+			name:    "optional params at the root",
+			pattern: "/:lang?",
+			expect:  "/",
+		},
+		{
+			name:    "missing param",
+			pattern: "/users/:id",
+			err:     "missing param id",
+		},
+		{
+			name:    "invalid param",
+			pattern: "/users/:id<int>",
+			values:  map[string]string{"id": "abc"},
+			err:     `param id: "abc" does not match :id<int>`,
+		},
+		{
+			name:    "optional param after a missing one",
+			pattern: "/:lang?/:page?",
+			values:  map[string]string{"page": "2"},
+			err:     "param page is set without lang",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			path, used, err := Fill(tc.pattern, tc.values)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, path)
+			assert.Equal(t, tc.used, used)
+		})
+	}
+}
@@ -2,7 +2,6 @@ package js
 
 import (
 	"encoding/json"
-	"html"
 	"strings"
 )
 
@@ -12,12 +11,14 @@ const (
 
 type Operation []any
 
+// JS encodes ops for a phx-* attribute. The attribute escapes the value
+// when it is rendered.
 func JS(ops ...Operation) string {
 	jsonData, err := json.Marshal(ops)
 	if err != nil {
 		return ""
 	}
-	return html.EscapeString(string(jsonData))
+	return string(jsonData)
 }
 
 type PushArgs struct {
@@ -20,7 +20,7 @@ func TestJS(t *testing.T) {
 		{
 			name:     "single operation",
 			ops:      []Operation{{"push", map[string]string{"event": "test"}}},
-			expected: `[[&#34;push&#34;,{&#34;event&#34;:&#34;test&#34;}]]`,
+			expected: `[["push",{"event":"test"}]]`,
 		},
 		{
 			name: "multiple operations",
@@ -28,7 +28,7 @@ func TestJS(t *testing.T) {
 				{"show", nil},
 				{"hide", nil},
 			},
-			expected: `[[&#34;show&#34;,null],[&#34;hide&#34;,null]]`,
+			expected: `[["show",null],["hide",null]]`,
 		},
 	}
 
@@ -0,0 +1,95 @@
+package liveview
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// AsyncResult is the outcome of a function started with Socket.StartAsync
+// or Socket.AssignAsync.
+type AsyncResult struct {
+	Value any
+	Err   error
+}
+
+// AsyncAssign receives the result of Socket.AssignAsync in the liveview
+// goroutine, before the view handles it. See async.Async.
+type AsyncAssign interface {
+	SetAsyncResult(AsyncResult)
+}
+
+type asyncTask struct {
+	id     int64
+	cancel context.CancelFunc
+	assign AsyncAssign
+}
+
+// lastAsyncID makes task ids unique across liveviews, like the timer refs:
+// the results of a crashed liveview reach the one rejoining its topic.
+var lastAsyncID atomic.Int64
+
+// asyncTasks tracks the running async functions of a liveview by name.
+type asyncTasks struct {
+	tasks map[string]*asyncTask
+}
+
+func newAsyncTasks() *asyncTasks {
+	return &asyncTasks{
+		tasks: map[string]*asyncTask{},
+	}
+}
+
+// start registers a task under name, cancelling the one it replaces.
+func (a *asyncTasks) start(parent context.Context, name string, assign AsyncAssign) (context.Context, int64) {
+	a.cancel(name)
+
+	ctx, cancel := context.WithCancel(parent)
+
+	id := lastAsyncID.Add(1)
+	a.tasks[name] = &asyncTask{
+		id:     id,
+		cancel: cancel,
+		assign: assign,
+	}
+
+	return ctx, id
+}
+
+// finish removes the task and returns it, unless it was cancelled or
+// replaced since it started.
+func (a *asyncTasks) finish(name string, id int64) (*asyncTask, bool) {
+	task, ok := a.tasks[name]
+	if !ok || task.id != id {
+		return nil, false
+	}
+
+	delete(a.tasks, name)
+	task.cancel()
+
+	return task, true
+}
+
+func (a *asyncTasks) cancel(name string) {
+	if task, ok := a.tasks[name]; ok {
+		task.cancel()
+		delete(a.tasks, name)
+	}
+}
+
+func (a *asyncTasks) cancelAll() {
+	for name := range a.tasks {
+		a.cancel(name)
+	}
+}
+
+// runAsync calls f, turning a panic into an error.
+func runAsync(ctx context.Context, f func(context.Context) (any, error)) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("async panic: %v", r)
+		}
+	}()
+
+	return f(ctx)
+}
@@ -0,0 +1,192 @@
+package liveview
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-live-view/go-live-view/channel"
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/stretchr/testify/assert"
+)
+
+// selfSocket delivers PushSelf payloads on a channel, like the mailbox
+// of a liveview. It never blocks and drops payloads once full.
+type selfSocket struct {
+	channel.Socket
+	self chan params.Params
+}
+
+func (s *selfSocket) Push(string, any) error {
+	return nil
+}
+
+func (s *selfSocket) PushSelf(event string, payload any) error {
+	select {
+	case s.self <- params.FromAny(payload):
+	default:
+	}
+	return nil
+}
+
+type fakeRoute struct {
+	view       View
+	middleware []Middleware
+	layout     func(...rend.Node) rend.Node
+}
+
+func (r *fakeRoute) GetView() View                                  { return r.view }
+func (r *fakeRoute) GetParams() params.Params                       { return params.Params{} }
+func (r *fakeRoute) GetMounts() []func(Socket, params.Params) error { return nil }
+func (r *fakeRoute) GetMiddleware() []Middleware                    { return r.middleware }
+func (r *fakeRoute) GetLayout() func(...rend.Node) rend.Node        { return r.layout }
+func (r *fakeRoute) GetHttpMounts() []func(http.ResponseWriter, *http.Request, params.Params) error {
+	return nil
+}
+
+type asyncView struct {
+	assigned AsyncResult
+	handled  []string
+	results  []AsyncResult
+}
+
+func (v *asyncView) SetAsyncResult(r AsyncResult) {
+	v.assigned = r
+}
+
+func (v *asyncView) HandleAsync(s Socket, name string, r AsyncResult) error {
+	v.handled = append(v.handled, name)
+	v.results = append(v.results, r)
+	return nil
+}
+
+func (v *asyncView) Render(rend.Node) (rend.Node, error) {
+	return html.Div(), nil
+}
+
+func newAsyncLifecycle(view View) (*lifecycle, *selfSocket) {
+	l := NewLifecycle(nil, fakeTokenizer{}, nil)
+	l.route = &fakeRoute{view: view}
+	l.tree = rend.RenderTree(html.Div())
+
+	return l, &selfSocket{self: make(chan params.Params, 8)}
+}
+
+func TestAsync(t *testing.T) {
+	view := &asyncView{}
+	l, fake := newAsyncLifecycle(view)
+	s := l.Socket(fake)
+
+	s.AssignAsync("user", view, func(context.Context) (any, error) {
+		return "john", nil
+	})
+	s.StartAsync("fail", func(context.Context) (any, error) {
+		return nil, errors.New("failed")
+	})
+
+	for range 2 {
+		_, err := l.AsyncResult(s, <-fake.self)
+		assert.NoError(t, err)
+	}
+
+	assert.ElementsMatch(t, []string{"user", "fail"}, view.handled)
+	assert.Equal(t, AsyncResult{Value: "john"}, view.assigned)
+	assert.Empty(t, l.state.async.tasks)
+}
+
+func TestAsyncPanic(t *testing.T) {
+	view := &asyncView{}
+	l, fake := newAsyncLifecycle(view)
+	s := l.Socket(fake)
+
+	s.StartAsync("panic", func(context.Context) (any, error) {
+		panic("boom")
+	})
+
+	_, err := l.AsyncResult(s, <-fake.self)
+	assert.NoError(t, err)
+	assert.EqualError(t, view.results[0].Err, "async panic: boom")
+}
+
+func TestAsyncIDsAreUnique(t *testing.T) {
+	view := &asyncView{}
+	l, fake := newAsyncLifecycle(view)
+	s := l.Socket(fake)
+
+	other := &asyncView{}
+	ol, ofake := newAsyncLifecycle(other)
+	osock := ol.Socket(ofake)
+
+	s.StartAsync("user", func(context.Context) (any, error) {
+		return "old", nil
+	})
+	osock.StartAsync("user", func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	// a late result of a liveview that left reaches the one rejoining.
+	p := <-fake.self
+	assert.NoError(t, l.Leave())
+
+	diff, err := ol.AsyncResult(osock, p)
+	assert.NoError(t, err)
+	assert.Nil(t, diff)
+	assert.Empty(t, other.handled)
+
+	assert.NoError(t, ol.Leave())
+}
+
+func TestAsyncCancel(t *testing.T) {
+	tt := []struct {
+		name   string
+		cancel func(*lifecycle, Socket)
+	}{
+		{
+			name: "cancel async",
+			cancel: func(l *lifecycle, s Socket) {
+				s.CancelAsync("user")
+			},
+		},
+		{
+			name: "leave",
+			cancel: func(l *lifecycle, s Socket) {
+				assert.NoError(t, l.Leave())
+			},
+		},
+		{
+			name: "restart under the same name",
+			cancel: func(l *lifecycle, s Socket) {
+				s.StartAsync("user", func(ctx context.Context) (any, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				})
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			view := &asyncView{}
+			l, fake := newAsyncLifecycle(view)
+			s := l.Socket(fake)
+
+			s.StartAsync("user", func(ctx context.Context) (any, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+
+			tc.cancel(l, s)
+
+			diff, err := l.AsyncResult(s, <-fake.self)
+			assert.NoError(t, err)
+			assert.Nil(t, diff)
+			assert.Empty(t, view.handled)
+
+			l.state.async.cancelAll()
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package liveview
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+)
+
+// Component is a stateful component rendered with LiveComponent. It keeps
+// its state across renders of the parent and handles the events targeted
+// at it with phx-target.
+type Component interface {
+	Render() (rend.Node, error)
+}
+
+type ComponentMounter interface {
+	Mount(Socket) error
+}
+
+type ComponentUpdater interface {
+	Update(Socket, params.Params) error
+}
+
+type ComponentEventHandler interface {
+	HandleEvent(Socket, string, params.Params) error
+}
+
+func TryComponentMount(a any, s Socket) error {
+	if m, ok := a.(ComponentMounter); ok {
+		return m.Mount(s)
+	}
+
+	return nil
+}
+
+func TryComponentUpdate(a any, s Socket, p params.Params) error {
+	if m, ok := a.(ComponentUpdater); ok {
+		return m.Update(s, p)
+	}
+
+	return nil
+}
+
+func TryComponentEvent(a any, s Socket, event string, p params.Params) error {
+	if m, ok := a.(ComponentEventHandler); ok {
+		return m.HandleEvent(s, event, p)
+	}
+
+	return nil
+}
+
+// Target returns the phx-target attribute sending events to the component
+// whose root element has the given DOM id.
+func Target(id string) *html.AttributeNode {
+	return html.Attr("phx-target", "#"+id)
+}
+
+type componentsKey struct{}
+
+// LiveComponent renders the component with the given id. c is only used
+// the first time the id is rendered, it is mounted and then updated with
+// assigns whenever they change.
+func LiveComponent(id string, c Component, assigns params.Params) rend.Node {
+	return &liveComponentNode{
+		id:        id,
+		component: c,
+		assigns:   assigns,
+	}
+}
+
+type liveComponentNode struct {
+	id        string
+	component Component
+	assigns   params.Params
+}
+
+func (n *liveComponentNode) Render(diff bool, root *rend.Root, t *rend.Rend, b *strings.Builder) error {
+	reg, ok := root.Value(componentsKey{}).(*components)
+	if !ok {
+		reg = newComponents()
+	}
+
+	entry, err := reg.render(root, n)
+	if err != nil {
+		reg.fail(err)
+		return err
+	}
+
+	node, err := entry.component.Render()
+	if err != nil {
+		reg.fail(err)
+		return err
+	}
+
+	if node == nil {
+		return nil
+	}
+
+	if diff {
+		t.AddComponentCID(root, entry.cid, rend.Render(root, node))
+		t.AddStatic(b.String())
+		b.Reset()
+
+		return nil
+	}
+
+	return node.Render(diff, root, t, b)
+}
+
+type componentEntry struct {
+	id        string
+	cid       int64
+	component Component
+	assigns   params.Params
+}
+
+// components tracks the live components of a liveview by ID and CID.
+type components struct {
+	byID   map[string]*componentEntry
+	byCID  map[int64]*componentEntry
+	maxCID int64
+
+	// socket and err are set for the duration of a render.
+	socket Socket
+	err    error
+}
+
+func newComponents() *components {
+	return &components{
+		byID:  map[string]*componentEntry{},
+		byCID: map[int64]*componentEntry{},
+	}
+}
+
+// bind prepares root to render the components with s.
+func (c *components) bind(root *rend.Root, s Socket) {
+	c.socket = s
+	c.err = nil
+
+	root.SkipCIDs(c.maxCID)
+	root.SetValue(componentsKey{}, c)
+}
+
+func (c *components) fail(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// render returns the entry of the node, mounting it when the id is new and
+// updating it when its assigns changed.
+func (c *components) render(root *rend.Root, n *liveComponentNode) (*componentEntry, error) {
+	entry, ok := c.byID[n.id]
+	if !ok {
+		if n.component == nil {
+			return nil, fmt.Errorf("component %s is nil", n.id)
+		}
+
+		entry = &componentEntry{
+			id:        n.id,
+			cid:       root.NextCID(),
+			component: n.component,
+		}
+
+		c.byID[entry.id] = entry
+		c.byCID[entry.cid] = entry
+		c.maxCID = max(c.maxCID, entry.cid)
+
+		err := TryComponentMount(entry.component, c.socket)
+		if err != nil {
+			return nil, err
+		}
+	} else if reflect.DeepEqual(entry.assigns, n.assigns) {
+		return entry, nil
+	}
+
+	entry.assigns = n.assigns
+
+	return entry, TryComponentUpdate(entry.component, c.socket, n.assigns)
+}
+
+func (c *components) getByCID(cid int64) (*componentEntry, error) {
+	entry, ok := c.byCID[cid]
+	if !ok {
+		return nil, fmt.Errorf("component with cid %d not found", cid)
+	}
+
+	return entry, nil
+}
+
+func (c *components) getByID(id string) (*componentEntry, error) {
+	entry, ok := c.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("component %s not found", id)
+	}
+
+	return entry, nil
+}
+
+// destroy removes the component with cid and returns whether it existed.
+func (c *components) destroy(cid int64) (bool, error) {
+	entry, ok := c.byCID[cid]
+	if !ok {
+		return false, nil
+	}
+
+	delete(c.byCID, cid)
+	delete(c.byID, entry.id)
+
+	return true, TryUnmount(entry.component)
+}
+
+// destroyAll removes all components, e.g. when the liveview leaves.
+func (c *components) destroyAll() error {
+	for cid := range c.byCID {
+		_, err := c.destroy(cid)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package liveview
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/go-live-view/go-live-view/dynamic"
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/stretchr/testify/assert"
+)
+
+type counterComponent struct {
+	mounts    int
+	updates   int
+	unmounted bool
+	label     string
+	count     int
+}
+
+func (c *counterComponent) Mount(s Socket) error {
+	c.mounts++
+	return nil
+}
+
+func (c *counterComponent) Update(s Socket, p params.Params) error {
+	c.updates++
+	c.label = p.String("label")
+	return nil
+}
+
+func (c *counterComponent) HandleEvent(s Socket, event string, p params.Params) error {
+	if event == "inc" {
+		c.count++
+	}
+	return nil
+}
+
+func (c *counterComponent) Unmount() error {
+	c.unmounted = true
+	return nil
+}
+
+func (c *counterComponent) Render() (rend.Node, error) {
+	return html.Div(
+		dynamic.Text(c.label),
+		dynamic.Text(strconv.Itoa(c.count)),
+	), nil
+}
+
+type componentsView struct {
+	ids   []string
+	label string
+	made  map[string]*counterComponent
+}
+
+func (v *componentsView) Render(rend.Node) (rend.Node, error) {
+	children := []rend.Node{
+		dynamic.Component(html.Span(html.Text("anonymous"))),
+	}
+
+	for _, id := range v.ids {
+		c := &counterComponent{}
+		if v.made[id] == nil {
+			v.made[id] = c
+		}
+
+		children = append(children, LiveComponent(id, c, params.Params{"label": v.label}))
+	}
+
+	return html.Div(children...), nil
+}
+
+func TestLiveComponents(t *testing.T) {
+	l := NewLifecycle(nil, fakeTokenizer{}, nil)
+	s := l.Socket(&fakeSocket{})
+
+	view := &componentsView{
+		ids:   []string{"a", "b"},
+		label: "first",
+		made:  map[string]*counterComponent{},
+	}
+
+	tree, err := l.render(view, s)
+	assert.NoError(t, err)
+	l.tree = tree
+
+	a, b := l.components.byID["a"], l.components.byID["b"]
+	assert.Same(t, view.made["a"], a.component)
+	assert.Equal(t, 1, view.made["a"].mounts)
+	assert.Equal(t, 1, view.made["a"].updates)
+	assert.Contains(t, tree.Components, a.cid)
+	assert.Contains(t, tree.Components, b.cid)
+
+	// same assigns keep the component untouched and its cid stable
+	view.ids = []string{"b", "a"}
+	tree, err = l.render(view, s)
+	assert.NoError(t, err)
+	l.tree = tree
+
+	assert.Equal(t, a.cid, l.components.byID["a"].cid)
+	assert.Equal(t, b.cid, l.components.byID["b"].cid)
+	assert.Equal(t, 1, view.made["a"].mounts)
+	assert.Equal(t, 1, view.made["a"].updates)
+
+	// changed assigns update the component
+	view.label = "second"
+	tree, err = l.render(view, s)
+	assert.NoError(t, err)
+	l.tree = tree
+
+	assert.Equal(t, 2, view.made["a"].updates)
+	assert.Equal(t, "second", view.made["a"].label)
+
+	// events with a cid go to the component
+	err = l.dispatchEvent(view, s, "inc", params.Params{"cid": float64(a.cid)})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, view.made["a"].count)
+	assert.Equal(t, 0, view.made["b"].count)
+
+	// rendered components survive cids_destroyed, removed ones are unmounted
+	view.ids = []string{"b"}
+	tree, err = l.render(view, s)
+	assert.NoError(t, err)
+	l.tree = tree
+
+	err = l.DestroyCIDs([]int{int(a.cid), int(b.cid)})
+	assert.NoError(t, err)
+	assert.True(t, view.made["a"].unmounted)
+	assert.False(t, view.made["b"].unmounted)
+	assert.NotContains(t, l.components.byID, "a")
+	assert.Contains(t, l.components.byID, "b")
+
+	// a component rendered again under the same id is mounted anew
+	view.ids = []string{"a", "b"}
+	delete(view.made, "a")
+	_, err = l.render(view, s)
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, a.cid, l.components.byID["a"].cid)
+	assert.Equal(t, 1, view.made["a"].mounts)
+}
+
+func TestLiveComponentCIDsDoNotCollide(t *testing.T) {
+	l := NewLifecycle(nil, fakeTokenizer{}, nil)
+
+	view := &componentsView{
+		ids:  []string{"a"},
+		made: map[string]*counterComponent{},
+	}
+
+	for i := 0; i < 2; i++ {
+		tree, err := l.render(view, nil)
+		assert.NoError(t, err)
+
+		// the anonymous component and the live component
+		assert.Len(t, tree.Components, 2)
+	}
+}
@@ -0,0 +1,58 @@
+package liveview
+
+import (
+	"context"
+	"sync"
+)
+
+type valuesKey struct{}
+
+// valuesContext is a context middleware can add values to after it was
+// created, see PutValue.
+type valuesContext struct {
+	context.Context
+
+	mu     sync.RWMutex
+	values map[any]any
+}
+
+func newValuesContext(ctx context.Context) *valuesContext {
+	return &valuesContext{
+		Context: ctx,
+		values:  map[any]any{},
+	}
+}
+
+func (c *valuesContext) Value(key any) any {
+	if key == (valuesKey{}) {
+		return c
+	}
+
+	c.mu.RLock()
+	v, ok := c.values[key]
+	c.mu.RUnlock()
+
+	if ok {
+		return v
+	}
+
+	return c.Context.Value(key)
+}
+
+// PutValue adds a value to the liveview context ctx derives from. In a
+// WithHttpMount middleware, use r.Context() and the value is seen by the
+// rest of the HTTP request. In a WithMount middleware, use s.Context() and
+// the value is seen by all later messages of the liveview, e.g. the
+// authenticated user. It returns false if ctx is not a liveview context.
+func PutValue(ctx context.Context, key, value any) bool {
+	c, ok := ctx.Value(valuesKey{}).(*valuesContext)
+	if !ok {
+		return false
+	}
+
+	c.mu.Lock()
+	c.values[key] = value
+	c.mu.Unlock()
+
+	return true
+}
@@ -0,0 +1,40 @@
+package liveview
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userKey struct{}
+
+func TestPutValue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := NewLifecycle(nil, fakeTokenizer{}, nil, WithContext(ctx))
+
+	s, done := l.Begin(&fakeSocket{})
+	assert.True(t, PutValue(s.Context(), userKey{}, "john"))
+	done()
+
+	assert.Error(t, s.Context().Err())
+
+	// values outlive the message they were added in
+	s, done = l.Begin(&fakeSocket{})
+	defer done()
+
+	assert.Equal(t, "john", s.Context().Value(userKey{}))
+	assert.NoError(t, s.Context().Err())
+
+	cancel()
+	assert.Error(t, s.Context().Err())
+}
+
+func TestPutValueWithoutLiveview(t *testing.T) {
+	ctx := context.Background()
+
+	assert.False(t, PutValue(ctx, userKey{}, "john"))
+	assert.Nil(t, ctx.Value(userKey{}))
+}
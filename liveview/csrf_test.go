@@ -0,0 +1,137 @@
+package liveview
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-live-view/go-live-view/csrf"
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSecret(t *testing.T) string {
+	t.Helper()
+
+	secret, err := csrf.Secret(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.NoError(t, err)
+
+	return secret
+}
+
+func newToken(t *testing.T, secret string) string {
+	t.Helper()
+
+	token, err := csrf.Token(secret)
+	assert.NoError(t, err)
+
+	return token
+}
+
+func TestJoinCSRF(t *testing.T) {
+	secret := newSecret(t)
+
+	tt := []struct {
+		name   string
+		secret string
+		join   params.Params
+		err    error
+	}{
+		{
+			name:   "valid token",
+			secret: secret,
+			join: params.Params{
+				"session": "s",
+				"params":  map[string]any{csrf.ParamName: newToken(t, secret)},
+			},
+		},
+		{
+			name:   "missing session",
+			secret: secret,
+			join: params.Params{
+				"params": map[string]any{csrf.ParamName: newToken(t, secret)},
+			},
+			err: ErrInvalidToken,
+		},
+		{
+			name:   "missing token",
+			secret: secret,
+			join:   params.Params{"session": "s"},
+			err:    csrf.ErrInvalidToken,
+		},
+		{
+			name:   "forged token",
+			secret: secret,
+			join: params.Params{
+				"session": "s",
+				"params":  map[string]any{csrf.ParamName: "forged"},
+			},
+			err: csrf.ErrInvalidToken,
+		},
+		{
+			name:   "token of another session",
+			secret: secret,
+			join: params.Params{
+				"session": "s",
+				"params":  map[string]any{csrf.ParamName: newToken(t, newSecret(t))},
+			},
+			err: csrf.ErrInvalidToken,
+		},
+		{
+			name: "connected without cookie",
+			join: params.Params{
+				"session": "s",
+				"params":  map[string]any{csrf.ParamName: newToken(t, secret)},
+			},
+			err: csrf.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			l := NewLifecycle(
+				&fakeRouter{route: &fakeRoute{view: &loginView{}}},
+				fakeTokenizer{},
+				fakeSession{},
+				WithCSRFSecret(tc.secret),
+			)
+
+			tc.join["url"] = "/"
+
+			_, err := l.Join(l.Socket(&fakeSocket{}), tc.join)
+			if tc.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestStaticRenderCSRFMeta(t *testing.T) {
+	l := NewLifecycle(
+		&fakeRouter{route: &fakeRoute{view: &loginView{}, layout: func(children ...rend.Node) rend.Node {
+			return html.Html(html.Head(csrf.Meta()), html.Body(children...))
+		}}},
+		fakeTokenizer{},
+		fakeSession{},
+	)
+
+	rec := httptest.NewRecorder()
+	page, err := l.StaticRender(rec, httptest.NewRequest("GET", "/", nil))
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest("GET", "/live/websocket", nil)
+	r.AddCookie(rec.Result().Cookies()[0])
+	secret := csrf.CookieSecret(r)
+
+	_, token, ok := strings.Cut(page, `<meta name="csrf-token" content="`)
+	assert.True(t, ok)
+	token, _, _ = strings.Cut(token, `"`)
+
+	assert.True(t, csrf.Verify(secret, token))
+	assert.NotContains(t, page, secret)
+}
@@ -4,3 +4,25 @@ type HttpError interface {
 	Code() int
 	Error() string
 }
+
+type httpError struct {
+	code    int
+	message string
+}
+
+// NewHttpError returns an error responding to the HTTP render with code
+// and message.
+func NewHttpError(code int, message string) HttpError {
+	return &httpError{
+		code:    code,
+		message: message,
+	}
+}
+
+func (e *httpError) Code() int {
+	return e.code
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
@@ -0,0 +1,104 @@
+package liveview
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-live-view/go-live-view/params"
+)
+
+var ErrUnknownEvent = errors.New("unknown event")
+
+// EventBinding binds an event to a typed handler, see On.
+type EventBinding struct {
+	event  string
+	handle func(Socket, params.Params) error
+}
+
+// EventBinder is implemented by views and live components declaring their
+// event handlers with On instead of switching on the event in Event.
+type EventBinder interface {
+	Events() []EventBinding
+}
+
+// On binds event to handle. The event value is decoded into T with
+// params.Decode, use params.Params as T to get it as is.
+//
+//	func (l *Live) Events() []lv.EventBinding {
+//		return []lv.EventBinding{
+//			lv.On("save", l.save),
+//		}
+//	}
+func On[T any](event string, handle func(Socket, T) error) EventBinding {
+	return EventBinding{
+		event: event,
+		handle: func(s Socket, p params.Params) error {
+			var in T
+
+			err := params.Decode(eventValue(p), &in)
+			if err != nil {
+				return fmt.Errorf("event %s: %w", event, err)
+			}
+
+			return handle(s, in)
+		},
+	}
+}
+
+// eventValue returns the value of an event. Forms send their fields url
+// encoded.
+func eventValue(p params.Params) params.Params {
+	raw, ok := p["value"].(string)
+	if !ok {
+		return p.Map("value")
+	}
+
+	value, err := params.ParseQuery(raw)
+	if err != nil {
+		return params.Params{}
+	}
+
+	return value
+}
+
+// TryBoundEvent calls the handler a binds to event with On. It returns
+// false if a binds no handler to event.
+func TryBoundEvent(a any, s Socket, event string, p params.Params) (bool, error) {
+	b, ok := a.(EventBinder)
+	if !ok {
+		return false, nil
+	}
+
+	for _, binding := range b.Events() {
+		if binding.event == event {
+			return true, binding.handle(s, p)
+		}
+	}
+
+	return false, nil
+}
+
+// DispatchEvent sends event to the handler a binds with On, or else to its
+// Event method. An event neither is found for fails with ErrUnknownEvent
+// when a is an EventBinder.
+func DispatchEvent(a any, s Socket, event string, p params.Params) error {
+	handled, err := TryBoundEvent(a, s, event, p)
+	if handled {
+		return err
+	}
+
+	if _, ok := a.(EventHandler); ok {
+		return TryEvent(a, s, event, p)
+	}
+
+	if _, ok := a.(EventBinder); ok {
+		return UnknownEvent(event)
+	}
+
+	return nil
+}
+
+// UnknownEvent returns the error for an event no handler is bound to.
+func UnknownEvent(event string) error {
+	return fmt.Errorf("%w: %s", ErrUnknownEvent, event)
+}
@@ -0,0 +1,93 @@
+package liveview
+
+import (
+	"testing"
+
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/stretchr/testify/assert"
+)
+
+type saveForm struct {
+	Name  string `param:"name"`
+	Count int    `param:"count"`
+}
+
+type boundView struct {
+	saved []saveForm
+	raw   params.Params
+}
+
+func (v *boundView) Events() []EventBinding {
+	return []EventBinding{
+		On("save", func(s Socket, in saveForm) error {
+			v.saved = append(v.saved, in)
+			return nil
+		}),
+		On("raw", func(s Socket, p params.Params) error {
+			v.raw = p
+			return nil
+		}),
+	}
+}
+
+func (v *boundView) Render(rend.Node) (rend.Node, error) {
+	return nil, nil
+}
+
+type boundHandlerView struct {
+	boundView
+	events []string
+}
+
+func (v *boundHandlerView) Event(s Socket, event string, p params.Params) error {
+	v.events = append(v.events, event)
+	return nil
+}
+
+func TestDispatchEvent(t *testing.T) {
+	s := NewSocket(&fakeSocket{})
+	view := &boundView{}
+
+	err := DispatchEvent(view, s, "save", params.Params{
+		"type":  "click",
+		"value": map[string]any{"name": "a", "count": float64(1)},
+	})
+	assert.NoError(t, err)
+
+	err = DispatchEvent(view, s, "save", params.Params{
+		"type":  "form",
+		"value": "name=b&count=2",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []saveForm{{"a", 1}, {"b", 2}}, view.saved)
+
+	err = DispatchEvent(view, s, "raw", params.Params{
+		"value": map[string]any{"id": "1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, params.Params{"id": "1"}, view.raw)
+
+	err = DispatchEvent(view, s, "save", params.Params{
+		"value": map[string]any{"count": "many"},
+	})
+	assert.EqualError(t, err, `event save: params: count: invalid integer "many"`)
+
+	err = DispatchEvent(view, s, "delete", params.Params{})
+	assert.ErrorIs(t, err, ErrUnknownEvent)
+}
+
+func TestDispatchEventFallsBackToEvent(t *testing.T) {
+	s := NewSocket(&fakeSocket{})
+	view := &boundHandlerView{}
+
+	err := DispatchEvent(view, s, "save", params.Params{})
+	assert.NoError(t, err)
+
+	err = DispatchEvent(view, s, "delete", params.Params{})
+	assert.NoError(t, err)
+
+	assert.Len(t, view.saved, 1)
+	assert.Equal(t, []string{"delete"}, view.events)
+}
@@ -0,0 +1,39 @@
+package liveview
+
+import "maps"
+
+const flashSalt = "lv:flash"
+
+// Flash holds messages shown to the user once, keyed by kind such as
+// "info" or "error".
+type Flash map[string]string
+
+// Get returns the message of kind.
+func (f Flash) Get(kind string) string {
+	return f[kind]
+}
+
+// replace swaps the messages in place so views holding the map see the
+// new content.
+func (f Flash) replace(other Flash) {
+	clear(f)
+	maps.Copy(f, other)
+}
+
+func (l *lifecycle) encodeFlash(f Flash) (string, error) {
+	return l.tokenizer.Encode(flashSalt, f)
+}
+
+func (l *lifecycle) decodeFlash(token string) Flash {
+	decode := Flash{}
+	if token == "" {
+		return decode
+	}
+
+	err := l.tokenizer.Decode(flashSalt, token, &decode)
+	if err != nil {
+		return Flash{}
+	}
+
+	return decode
+}
@@ -0,0 +1,158 @@
+package liveview
+
+import (
+	"testing"
+
+	"github.com/go-live-view/go-live-view/channel"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/stretchr/testify/assert"
+)
+
+type push struct {
+	event   string
+	payload any
+}
+
+type fakeSocket struct {
+	channel.Socket
+	pushes []push
+}
+
+func (f *fakeSocket) Push(event string, payload any) error {
+	f.pushes = append(f.pushes, push{event, payload})
+	return nil
+}
+
+func (f *fakeSocket) PushSelf(event string, payload any) error {
+	return nil
+}
+
+type fakeTokenizer struct{}
+
+func (fakeTokenizer) Encode(salt string, v any) (string, error) {
+	return salt, nil
+}
+
+func (fakeTokenizer) Decode(salt, token string, v any) error {
+	return nil
+}
+
+func TestFlash(t *testing.T) {
+	s := NewSocket(&fakeSocket{})
+
+	s.PutFlash("info", "saved")
+	s.PutFlash("error", "failed")
+	assert.Equal(t, Flash{"info": "saved", "error": "failed"}, s.Flash())
+
+	s.ClearFlash("error")
+	assert.Equal(t, Flash{"info": "saved"}, s.Flash())
+
+	s.ClearFlash()
+	assert.Empty(t, s.Flash())
+}
+
+func TestFlashSharedByLifecycle(t *testing.T) {
+	l := NewLifecycle(nil, fakeTokenizer{}, nil)
+
+	flash := l.Socket(&fakeSocket{}).Flash()
+
+	l.Socket(&fakeSocket{}).PutFlash("info", "saved")
+	assert.Equal(t, "saved", flash.Get("info"))
+
+	l.state.flash.replace(Flash{"error": "failed"})
+	assert.Equal(t, Flash{"error": "failed"}, flash)
+}
+
+func TestFlashRedirects(t *testing.T) {
+	tt := []struct {
+		name     string
+		redirect func(Socket) error
+		event    string
+		flash    any
+	}{
+		{
+			name: "patch keeps the flash on the socket",
+			redirect: func(s Socket) error {
+				return s.PushPatch("/", WithFlash("info", "patched"))
+			},
+			event: "live_patch",
+			flash: nil,
+		},
+		{
+			name: "navigate carries the flash",
+			redirect: func(s Socket) error {
+				return s.PushNavigate("/", WithFlash("info", "navigated"))
+			},
+			event: "live_redirect",
+			flash: flashSalt,
+		},
+		{
+			name: "redirect carries the flash",
+			redirect: func(s Socket) error {
+				return s.Redirect("/", WithFlash("info", "redirected"))
+			},
+			event: "redirect",
+			flash: flashSalt,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			l := NewLifecycle(nil, fakeTokenizer{}, nil)
+			fake := &fakeSocket{}
+			s := l.Socket(fake)
+
+			err := tc.redirect(s)
+			assert.NoError(t, err)
+			assert.True(t, s.Redirected())
+
+			assert.Len(t, fake.pushes, 1)
+			assert.Equal(t, tc.event, fake.pushes[0].event)
+
+			payload := fake.pushes[0].payload.(map[string]any)
+			assert.Equal(t, tc.flash, payload["flash"])
+
+			assert.NotEmpty(t, s.Flash().Get("info"))
+		})
+	}
+}
+
+func TestPushPatchParams(t *testing.T) {
+	fake := &fakeSocket{}
+	s := NewSocket(fake)
+
+	err := s.PushPatchParams("/search", params.Params{
+		"q":      "go live",
+		"filter": map[string]any{"tags": []string{"a", "b"}},
+	})
+	assert.NoError(t, err)
+
+	payload := fake.pushes[0].payload.(map[string]any)
+	assert.Equal(t, "/search?filter[tags][]=a&filter[tags][]=b&q=go+live", payload["to"])
+
+	err = s.PushPatchParams("/search", nil)
+	assert.NoError(t, err)
+
+	payload = fake.pushes[1].payload.(map[string]any)
+	assert.Equal(t, "/search", payload["to"])
+
+	err = s.PushPatchParams("/search?q=old&page=2#results", params.Params{"q": "new"})
+	assert.NoError(t, err)
+
+	payload = fake.pushes[2].payload.(map[string]any)
+	assert.Equal(t, "/search?page=2&q=new#results", payload["to"])
+
+	err = s.PushPatchParams("/search?q=%zz", params.Params{"q": "new"})
+	assert.Error(t, err)
+}
+
+func TestFlashWithoutMessages(t *testing.T) {
+	fake := &fakeSocket{}
+	s := NewLifecycle(nil, fakeTokenizer{}, nil).Socket(fake)
+
+	err := s.Redirect("/")
+	assert.NoError(t, err)
+
+	_, ok := fake.pushes[0].payload.(map[string]any)["flash"]
+	assert.False(t, ok)
+}
@@ -0,0 +1,54 @@
+package liveview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+)
+
+type urlBuilderKey struct{}
+
+// setURLBuilder stores the router of the page rendered with root for Href.
+func setURLBuilder(root *rend.Root, r Router) {
+	if b, ok := r.(URLBuilder); ok {
+		root.SetValue(urlBuilderKey{}, b)
+	}
+}
+
+// Href renders the href attribute of the route named name, filling its
+// params from p like Socket.URL. Unlike Socket.URL it works in Render,
+// where the URL is built when the page renders.
+func Href(name string, p params.Params) rend.Node {
+	// wrapped so that elements take it as an attribute.
+	return html.Attrs(&href{name: name, params: p})
+}
+
+type href struct {
+	name   string
+	params params.Params
+}
+
+func (h *href) Render(diff bool, root *rend.Root, t *rend.Rend, b *strings.Builder) error {
+	url, err := h.url(root)
+	if err != nil {
+		// the render fails like it does for components.
+		if c, ok := root.Value(componentsKey{}).(*components); ok {
+			c.fail(err)
+		}
+		return err
+	}
+
+	return html.HrefAttr(url).Render(diff, root, t, b)
+}
+
+func (h *href) url(root *rend.Root) (string, error) {
+	builder, ok := root.Value(urlBuilderKey{}).(URLBuilder)
+	if !ok {
+		return "", fmt.Errorf("liveview: no router to build the url of %s", h.name)
+	}
+
+	return builder.URL(h.name, h.params)
+}
@@ -0,0 +1,75 @@
+package liveview
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/stretchr/testify/assert"
+)
+
+// urlRouter builds /items/:id for the route named item.
+type urlRouter struct {
+	fakeRouter
+}
+
+func (r *urlRouter) URL(name string, p params.Params) (string, error) {
+	if name != "item" {
+		return "", fmt.Errorf("unknown route %s", name)
+	}
+
+	return "/items/" + p.String("id"), nil
+}
+
+func TestHref(t *testing.T) {
+	tt := []struct {
+		name   string
+		router Router
+		route  string
+		expect string
+		err    string
+	}{
+		{
+			name:   "named route",
+			router: &urlRouter{},
+			route:  "item",
+			expect: `<a href="/items/1">item</a>`,
+		},
+		{
+			name:   "unknown route",
+			router: &urlRouter{},
+			route:  "user",
+			err:    "unknown route user",
+		},
+		{
+			name:   "router without urls",
+			router: &fakeRouter{},
+			route:  "item",
+			err:    "liveview: no router to build the url of item",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newComponents()
+
+			root := rend.NewRoot()
+			c.bind(root, nil)
+			setURLBuilder(root, tc.router)
+
+			page := rend.RenderRootString(root, html.A(
+				Href(tc.route, params.Params{"id": "1"}),
+				html.Text("item"),
+			))
+			if tc.err != "" {
+				assert.EqualError(t, c.err, tc.err)
+				return
+			}
+
+			assert.NoError(t, c.err)
+			assert.Equal(t, tc.expect, page)
+		})
+	}
+}
@@ -1,28 +1,44 @@
 package liveview
 
 import (
-	"encoding/base64"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/go-live-view/go-live-view/channel"
+	"github.com/go-live-view/go-live-view/csrf"
 	"github.com/go-live-view/go-live-view/html"
 	"github.com/go-live-view/go-live-view/params"
 	"github.com/go-live-view/go-live-view/rend"
+	"github.com/go-live-view/go-live-view/uploads"
 	"github.com/rs/xid"
 )
 
 const flashKey = "__phoenix_flash__"
 
+// salts bind tokens to their purpose.
+const (
+	sessionSalt = "lv:session"
+	staticSalt  = "lv:static"
+	uploadSalt  = "lv:upload"
+)
+
 var NotFoundError = errors.New("route not found")
 
+// ErrInvalidToken is returned for session and upload tokens that fail to
+// decode, e.g. signed with another key.
+var ErrInvalidToken = errors.New("invalid token")
+
 type Route interface {
 	GetView() View
 	GetParams() params.Params
 	GetHttpMounts() []func(http.ResponseWriter, *http.Request, params.Params) error
 	GetMounts() []func(Socket, params.Params) error
+	GetMiddleware() []Middleware
+	GetLayout() func(...rend.Node) rend.Node
 }
 
 type Router interface {
@@ -31,39 +47,140 @@ type Router interface {
 	GetLayout() func(...rend.Node) rend.Node
 }
 
+// URLBuilder is implemented by routers building the URLs of named routes,
+// see Socket.URL.
+type URLBuilder interface {
+	URL(string, params.Params) (string, error)
+}
+
 type sessionGetter interface {
 	Get(*http.Request) map[string]any
 }
 
 type tokenizer interface {
-	Encode(any) (string, error)
-	Decode(string, any) error
+	Encode(string, any) (string, error)
+	Decode(string, string, any) error
 }
 
-type lifecycle struct {
-	router    Router
-	route     Route
-	tree      *rend.Root
-	tokenizer tokenizer
-	session   sessionGetter
+type LifecycleOption func(*lifecycle)
 
-	firstJoin bool
+type lifecycle struct {
+	id         string
+	router     Router
+	route      Route
+	tree       *rend.Root
+	tokenizer  tokenizer
+	session    sessionGetter
+	state      *state
+	components *components
+
+	nav        *Navigation
+	noCSRF     bool
+	csrfSecret string
+
+	logger       *slog.Logger
+	filterParams []string
 }
 
 func NewLifecycle(
 	r Router,
 	tokenizer tokenizer,
 	session sessionGetter,
+	opts ...LifecycleOption,
 ) *lifecycle {
-	return &lifecycle{
-		router:    r,
-		tokenizer: tokenizer,
-		session:   session,
-		firstJoin: true,
+	l := &lifecycle{
+		id:           xid.New().String(),
+		router:       r,
+		tokenizer:    tokenizer,
+		session:      session,
+		state:        newState(),
+		components:   newComponents(),
+		nav:          NewNavigation(),
+		logger:       slog.Default(),
+		filterParams: []string{"password", "secret", "token"},
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// WithoutCSRFProtection disables the CSRF token check on join.
+func WithoutCSRFProtection() LifecycleOption {
+	return func(l *lifecycle) {
+		l.noCSRF = true
+	}
+}
+
+// WithCSRFSecret sets the secret of the csrf cookie the socket connected
+// with, see csrf.CookieSecret. Joins must send a token for it.
+func WithCSRFSecret(secret string) LifecycleOption {
+	return func(l *lifecycle) {
+		l.csrfSecret = secret
+	}
+}
+
+// WithContext sets the context of the connection. It should be cancelled
+// when the client disconnects.
+func WithContext(ctx context.Context) LifecycleOption {
+	return func(l *lifecycle) {
+		l.state.ctx = newValuesContext(ctx)
+	}
+}
+
+// WithLogger sets the logger of the lifecycle. Renders, joins, patches
+// and events are logged at the debug level with their duration. It
+// defaults to slog.Default.
+func WithLogger(logger *slog.Logger) LifecycleOption {
+	return func(l *lifecycle) {
+		l.logger = logger
+	}
+}
+
+// WithFilterParams adds keys to the keys whose values are not logged, see
+// params.Filter. These are password, secret and token by default.
+func WithFilterParams(keys ...string) LifecycleOption {
+	return func(l *lifecycle) {
+		l.filterParams = append(l.filterParams, keys...)
+	}
+}
+
+// WithOnlyFilterParams replaces the keys whose values are not logged,
+// without keys nothing is filtered.
+func WithOnlyFilterParams(keys ...string) LifecycleOption {
+	return func(l *lifecycle) {
+		l.filterParams = keys
+	}
+}
+
+// Socket returns a socket for s sharing the state of the liveview.
+func (l *lifecycle) Socket(s channel.Socket) Socket {
+	return &socket{
+		Socket:      s,
+		router:      l.router,
+		state:       l.state,
+		encodeFlash: l.encodeFlash,
 	}
 }
 
+// Begin returns the socket to handle one message with. Its context is
+// cancelled by the returned function once the message is handled.
+func (l *lifecycle) Begin(s channel.Socket) (Socket, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(l.state.ctx)
+
+	return &socket{
+		Socket:      s,
+		ctx:         ctx,
+		router:      l.router,
+		state:       l.state,
+		encodeFlash: l.encodeFlash,
+	}, cancel
+}
+
 func (l *lifecycle) Join(s Socket, p params.Params) (*rend.Root, error) {
+	start := time.Now()
 	url := p.String("url", "redirect")
 
 	route, err := l.router.GetRoute(url)
@@ -71,7 +188,7 @@ func (l *lifecycle) Join(s Socket, p params.Params) (*rend.Root, error) {
 		return render404(route, err)
 	}
 
-	if l.route != nil && !l.router.Routable(l.route, route) {
+	if prev := l.nav.current(); prev != nil && !l.router.Routable(prev, route) {
 		err := s.Redirect(url)
 		if err != nil {
 			return nil, err
@@ -80,33 +197,61 @@ func (l *lifecycle) Join(s Socket, p params.Params) (*rend.Root, error) {
 	}
 
 	l.route = route
+	l.nav.set(route)
+
+	// the title set by the previous view does not carry over.
+	l.state.pageTitle = ""
 
 	view := route.GetView()
 
+	session, err := l.decodeSession(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if !l.verifyCSRF(p) {
+		return nil, csrf.ErrInvalidToken
+	}
+
 	p = params.Merge(
 		p,
 		route.GetParams(),
-		l.decodeSession(p),
+		session.Values,
 	)
 
-	if l.firstJoin {
-		p = params.Merge(p, l.decodeStatic(p))
-		l.firstJoin = false
+	// the flash arrives in the static token after a redirect and in the
+	// join payload after a live navigation.
+	flash := l.decodeFlash(p.String("flash"))
+	delete(p, "flash")
+
+	if l.nav.first() {
+		if static := l.decodeStatic(p); len(static.Flash) > 0 {
+			flash = static.Flash
+		}
 	}
 
-	for _, mount := range route.GetMounts() {
-		err = mount(s, p)
+	l.state.flash.replace(flash)
+
+	c := &Conn{Socket: s, Params: p, Session: session.Values}
+
+	err = mount(route, c, func(c *Conn) error {
+		for _, mount := range route.GetMounts() {
+			err := mount(c.Socket, c.Params)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := TryMount(view, c.Socket, c.Params)
 		if err != nil {
-			return nil, err
+			return err
 		}
-	}
 
-	err = TryMount(view, s, p)
-	if err != nil {
-		return nil, err
+		return TryParams(view, c.Socket, c.Params)
+	})
+	if url, ok := redirectURL(err); ok {
+		err = s.Redirect(url)
 	}
-
-	err = TryParams(view, s, p)
 	if err != nil {
 		return nil, err
 	}
@@ -115,17 +260,22 @@ func (l *lifecycle) Join(s Socket, p params.Params) (*rend.Root, error) {
 		return nil, nil
 	}
 
-	node, err := view.Render(nil)
+	l.tree, err = l.render(view, s)
 	if err != nil {
 		return nil, err
 	}
 
-	l.tree = rend.RenderTree(node)
+	l.debug("liveview join", start,
+		"view", viewType{view},
+		"path", logPath(url),
+		"params", l.filtered(route.GetParams()),
+	)
 
 	return l.tree, nil
 }
 
 func (l *lifecycle) Params(s Socket, p params.Params) (*rend.Root, error) {
+	start := time.Now()
 	url := p.String("url", "redirect")
 
 	route, err := l.router.GetRoute(url)
@@ -142,6 +292,7 @@ func (l *lifecycle) Params(s Socket, p params.Params) (*rend.Root, error) {
 	}
 
 	l.route = route
+	l.nav.set(route)
 
 	view := route.GetView()
 
@@ -159,21 +310,22 @@ func (l *lifecycle) Params(s Socket, p params.Params) (*rend.Root, error) {
 		return nil, nil
 	}
 
-	node, err := view.Render(nil)
+	diff, err := l.diff(view, s)
 	if err != nil {
 		return nil, err
 	}
 
-	newTree := rend.RenderTree(node)
-
-	diff := l.tree.Diff(newTree)
-
-	l.tree = newTree
+	l.debug("liveview patch", start,
+		"view", viewType{view},
+		"path", logPath(url),
+		"params", l.filtered(route.GetParams()),
+	)
 
 	return diff, nil
 }
 
 func (l *lifecycle) Event(s Socket, p params.Params) (*rend.Root, error) {
+	start := time.Now()
 	event := p.String("event")
 
 	view := l.route.GetView()
@@ -183,29 +335,70 @@ func (l *lifecycle) Event(s Socket, p params.Params) (*rend.Root, error) {
 		l.route.GetParams(),
 	)
 
-	if err := TryEvent(view, s, event, p); err != nil {
-		return nil, err
+	switch event {
+	case "lv:clear-flash":
+		key := p.Map("value").String("key")
+		if key == "" {
+			s.ClearFlash()
+		} else {
+			s.ClearFlash(key)
+		}
+	case "lv:flash":
+		value := p.Map("value")
+		s.PutFlash(value.String("key"), value.String("msg"))
+	default:
+		if err := l.dispatchEvent(view, s, event, p); err != nil {
+			return nil, err
+		}
 	}
 
 	if s.Redirected() {
 		return nil, nil
 	}
 
-	node, err := view.Render(nil)
+	diff, err := l.diff(view, s)
 	if err != nil {
 		return nil, err
 	}
 
-	newTree := rend.RenderTree(node)
+	l.debug("liveview event", start,
+		"view", viewType{view},
+		"event", event,
+		"params", l.filtered(eventValue(p)),
+	)
 
-	diff := l.tree.Diff(newTree)
+	return diff, nil
+}
 
-	l.tree = newTree
+// dispatchEvent sends events targeted at a live component to it and all
+// other events to the view.
+func (l *lifecycle) dispatchEvent(view View, s Socket, event string, p params.Params) error {
+	if _, ok := p["cid"]; !ok {
+		return DispatchEvent(view, s, event, p)
+	}
 
-	return diff, nil
+	entry, err := l.components.getByCID(int64(p.Int("cid")))
+	if err != nil {
+		return err
+	}
+
+	handled, err := TryBoundEvent(entry.component, s, event, p)
+	if handled {
+		return err
+	}
+
+	if _, ok := entry.component.(ComponentEventHandler); !ok {
+		if _, ok := entry.component.(EventBinder); ok {
+			return UnknownEvent(event)
+		}
+	}
+
+	return TryComponentEvent(entry.component, s, event, p)
 }
 
 func (l *lifecycle) StaticRender(w http.ResponseWriter, r *http.Request) (string, error) {
+	start := time.Now()
+
 	route, err := l.router.GetRoute(r.URL.String())
 	if err != nil {
 		return render404String(route, err)
@@ -213,37 +406,82 @@ func (l *lifecycle) StaticRender(w http.ResponseWriter, r *http.Request) (string
 
 	view := route.GetView()
 
-	p := route.GetParams()
+	// lets middleware add values with PutValue.
+	r = r.WithContext(newValuesContext(r.Context()))
 
-	for _, mount := range route.GetHttpMounts() {
-		err = mount(w, r, p)
+	var csrfToken string
+	if !l.noCSRF {
+		secret, err := csrf.Secret(w, r)
 		if err != nil {
 			return "", err
 		}
-	}
 
-	err = TryHttpMount(view, w, r, p)
-	if err != nil {
-		return "", err
-	}
+		csrfToken, err = csrf.Token(secret)
+		if err != nil {
+			return "", err
+		}
 
-	err = TryMount(view, nil, p)
-	if err != nil {
-		return "", err
+		r = r.WithContext(csrf.NewContext(r.Context(), csrfToken))
 	}
 
-	err = TryParams(view, nil, p)
+	values := l.session.Get(r)
+
+	p := params.Merge(
+		route.GetParams(),
+		values,
+	)
+
+	c := &Conn{Writer: w, Request: r, Params: p, Session: values}
+
+	err = mount(route, c, func(c *Conn) error {
+		for _, mount := range route.GetHttpMounts() {
+			err := mount(c.Writer, c.Request, c.Params)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := TryHttpMount(view, c.Writer, c.Request, c.Params)
+		if err != nil {
+			return err
+		}
+
+		err = TryMount(view, nil, c.Params)
+		if err != nil {
+			return err
+		}
+
+		return TryParams(view, nil, c.Params)
+	})
+	if url, ok := redirectURL(err); ok {
+		http.Redirect(w, c.Request, url, http.StatusFound)
+		return "", nil
+	}
 	if err != nil {
 		return "", err
 	}
 
+	r = c.Request
+
 	node, err := view.Render(nil)
 	if err != nil {
 		return "", err
 	}
 
-	return rend.RenderString(
-		l.router.GetLayout()(
+	root := rend.NewRoot()
+	root.Title = l.pageTitle(view)
+	l.components.bind(root, nil)
+	setURLBuilder(root, l.router)
+	csrf.SetToken(root, csrfToken)
+
+	layout := route.GetLayout()
+	if layout == nil {
+		layout = l.router.GetLayout()
+	}
+
+	page := rend.RenderRootString(
+		root,
+		layout(
 			html.Attrs(
 				html.DataAttr("phx-main"),
 				html.DataAttr("phx-session", l.encodeSession(r)),
@@ -254,22 +492,156 @@ func (l *lifecycle) StaticRender(w http.ResponseWriter, r *http.Request) (string
 			),
 			node,
 		),
-	), nil
+	)
+	if l.components.err != nil {
+		return "", l.components.err
+	}
+
+	l.debug("liveview static render", start,
+		"view", viewType{view},
+		"path", r.URL.Path,
+		"params", l.filtered(route.GetParams()),
+		"size", len(page),
+	)
+
+	return page, nil
+}
+
+// render renders view into a new tree carrying the page title. Live
+// components are mounted and updated with s.
+func (l *lifecycle) render(view View, s Socket) (*rend.Root, error) {
+	start := time.Now()
+
+	node, err := view.Render(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	root := rend.NewRoot()
+	l.components.bind(root, s)
+	setURLBuilder(root, l.router)
+
+	tree := rend.RenderRootTree(root, node)
+	if l.components.err != nil {
+		return nil, l.components.err
+	}
+
+	tree.Title = l.pageTitle(view)
+
+	l.debug("liveview render", start, "view", viewType{view})
+
+	return tree, nil
+}
+
+// diff renders view and returns the changes since the last render.
+func (l *lifecycle) diff(view View, s Socket) (*rend.Root, error) {
+	tree, err := l.render(view, s)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := l.tree.Diff(tree)
+
+	l.tree = tree
+
+	return diff, nil
+}
+
+func (l *lifecycle) pageTitle(view View) string {
+	if l.state.pageTitle != "" {
+		return l.state.pageTitle
+	}
+
+	return TryPageTitle(view)
 }
 
+// DestroyCIDs removes the live components the client removed from the
+// page. Components rendered again in the meantime are kept.
 func (l *lifecycle) DestroyCIDs(cids []int) error {
-	if l.tree.Components != nil {
-		for _, cid := range cids {
-			if l.tree.Components[int64(cid)] != nil {
-				return fmt.Errorf("component with cid %d found", cid)
-			}
+	for _, cid := range cids {
+		if l.tree != nil && l.tree.Components[int64(cid)] != nil {
+			continue
+		}
+
+		_, err := l.components.destroy(int64(cid))
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// UpdateComponent updates the live component with the assigns sent by
+// Socket.SendUpdate and returns the resulting diff.
+func (l *lifecycle) UpdateComponent(s Socket, p params.Params) (*rend.Root, error) {
+	entry, err := l.components.getByID(p.String("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = TryComponentUpdate(entry.component, s, p.Map("assigns"))
+	if err != nil {
+		return nil, err
+	}
+
+	return l.diff(l.route.GetView(), s)
+}
+
+// AsyncResult hands the result of a function started with StartAsync or
+// AssignAsync to the view and returns the resulting diff. Results of
+// cancelled functions are dropped and return no diff.
+func (l *lifecycle) AsyncResult(s Socket, p params.Params) (*rend.Root, error) {
+	name := p.String("name")
+
+	task, ok := l.state.async.finish(name, int64(p.Int("id")))
+	if !ok {
+		return nil, nil
+	}
+
+	result, _ := p["result"].(AsyncResult)
+	if task.assign != nil {
+		task.assign.SetAsyncResult(result)
+	}
+
+	view := l.route.GetView()
+
+	err := TryHandleAsync(view, s, name, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Redirected() {
+		return nil, nil
+	}
+
+	return l.diff(view, s)
+}
+
+// Timer handles an event sent by a timer, unless the timer was cancelled
+// in the meantime.
+func (l *lifecycle) Timer(s Socket, p params.Params) (*rend.Root, error) {
+	if !l.state.timers.fire(TimerRef(p.Int("ref"))) {
+		return nil, nil
+	}
+
+	return l.Event(s, p)
+}
+
 func (l *lifecycle) Leave() error {
+	l.state.async.cancelAll()
+	l.state.timers.cancelAll()
+
+	// the join failed before a view was mounted.
+	if l.route == nil {
+		return nil
+	}
+
+	err := l.components.destroyAll()
+	if err != nil {
+		return err
+	}
+
 	return TryUnmount(l.route.GetView())
 }
 
@@ -290,17 +662,11 @@ func (l *lifecycle) AllowUpload(s Socket, p params.Params) (any, error) {
 
 	cfg.OnAllowUploads(p)
 
-	node, err := view.Render(nil)
+	diff, err := l.diff(view, s)
 	if err != nil {
 		return nil, err
 	}
 
-	newTree := rend.RenderTree(node)
-
-	diff := l.tree.Diff(newTree)
-
-	l.tree = newTree
-
 	if cfg == nil {
 		return map[string]any{
 			"diff": diff,
@@ -315,6 +681,11 @@ func (l *lifecycle) AllowUpload(s Socket, p params.Params) (any, error) {
 		}, nil
 	}
 
+	entries, err := l.encodeUploadTokens(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]any{
 		"config": map[string]any{
 			"chunk_size":    cfg.ChunkSize,
@@ -322,7 +693,7 @@ func (l *lifecycle) AllowUpload(s Socket, p params.Params) (any, error) {
 			"max_file_size": cfg.MaxFileSize,
 		},
 		"diff":    diff,
-		"entries": cfg.PreflightEntries(),
+		"entries": entries,
 		"errors":  map[string]any{},
 		"ref":     cfg.Ref,
 	}, nil
@@ -366,22 +737,23 @@ func (l *lifecycle) Progress(s Socket, p params.Params) (*rend.Root, error) {
 		return nil, err
 	}
 
-	node, err := view.Render(nil)
+	diff, err := l.diff(view, s)
 	if err != nil {
 		return nil, err
 	}
 
-	newTree := rend.RenderTree(node)
-
-	diff := l.tree.Diff(newTree)
-
-	l.tree = newTree
-
 	return diff, nil
 }
 
+// sessionToken is the payload of the data-phx-session token.
+type sessionToken struct {
+	Values map[string]any `json:"v"`
+}
+
 func (l *lifecycle) encodeSession(r *http.Request) string {
-	data, err := l.tokenizer.Encode(l.session.Get(r))
+	data, err := l.tokenizer.Encode(sessionSalt, &sessionToken{
+		Values: l.session.Get(r),
+	})
 	if err != nil {
 		return ""
 	}
@@ -389,29 +761,87 @@ func (l *lifecycle) encodeSession(r *http.Request) string {
 	return data
 }
 
-func (l *lifecycle) decodeSession(p params.Params) map[string]any {
-	decode := map[string]any{}
+func (l *lifecycle) decodeSession(p params.Params) (*sessionToken, error) {
+	decode := &sessionToken{}
 
 	session := p.String("session")
 	if session == "" {
-		return decode
+		return nil, fmt.Errorf("%w: session: missing", ErrInvalidToken)
 	}
 	delete(p, "session")
 
-	err := l.tokenizer.Decode(session, &decode)
+	err := l.tokenizer.Decode(sessionSalt, session, decode)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("%w: session: %w", ErrInvalidToken, err)
 	}
 
-	return decode
+	return decode, nil
+}
+
+// verifyCSRF checks the token the client sends in its connect params
+// against the secret of the cookie the socket connected with.
+func (l *lifecycle) verifyCSRF(p params.Params) bool {
+	if l.noCSRF {
+		return true
+	}
+
+	return csrf.Verify(l.csrfSecret, p.Map("params").String(csrf.ParamName))
+}
+
+// uploadToken binds an upload to the liveview that allowed it.
+type uploadToken struct {
+	LiveView  string `json:"l"`
+	ConfigRef string `json:"c"`
+	Ref       string `json:"r"`
+}
+
+// encodeUploadTokens signs the tokens the client uses to join the upload
+// channel of each preflighted entry.
+func (l *lifecycle) encodeUploadTokens(cfg *uploads.Config) (map[string]string, error) {
+	tokens := map[string]string{}
+
+	for ref := range cfg.PreflightEntries() {
+		token, err := l.tokenizer.Encode(uploadSalt, &uploadToken{
+			LiveView:  l.id,
+			ConfigRef: cfg.Ref,
+			Ref:       ref,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		tokens[ref] = token
+	}
+
+	return tokens, nil
+}
+
+// DecodeUploadToken verifies an upload token and returns the config and
+// entry refs it was issued for. It fails for tokens of other liveviews.
+func (l *lifecycle) DecodeUploadToken(token string) (string, string, error) {
+	decode := &uploadToken{}
+
+	err := l.tokenizer.Decode(uploadSalt, token, decode)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: upload: %w", ErrInvalidToken, err)
+	}
+
+	if decode.LiveView != l.id {
+		return "", "", fmt.Errorf("%w: upload: issued by another liveview", ErrInvalidToken)
+	}
+
+	return decode.ConfigRef, decode.Ref, nil
+}
+
+type staticToken struct {
+	Flash Flash `json:"f,omitempty"`
 }
 
 func (l *lifecycle) encodeStatic(w http.ResponseWriter, r *http.Request) string {
-	encode := map[string]any{}
+	encode := &staticToken{}
 
 	if cookie, err := r.Cookie(flashKey); err == nil {
-		flash, _ := base64.StdEncoding.DecodeString(cookie.Value)
-		encode["flash"] = flash
+		encode.Flash = l.decodeFlash(cookie.Value)
 	}
 
 	http.SetCookie(w, &http.Cookie{
@@ -421,7 +851,7 @@ func (l *lifecycle) encodeStatic(w http.ResponseWriter, r *http.Request) string
 		Path:    "/",
 	})
 
-	data, err := l.tokenizer.Encode(encode)
+	data, err := l.tokenizer.Encode(staticSalt, encode)
 	if err != nil {
 		return ""
 	}
@@ -429,8 +859,8 @@ func (l *lifecycle) encodeStatic(w http.ResponseWriter, r *http.Request) string
 	return data
 }
 
-func (l *lifecycle) decodeStatic(p params.Params) map[string]any {
-	decode := map[string]any{}
+func (l *lifecycle) decodeStatic(p params.Params) *staticToken {
+	decode := &staticToken{}
 
 	static := p.String("static")
 	if static == "" {
@@ -438,37 +868,14 @@ func (l *lifecycle) decodeStatic(p params.Params) map[string]any {
 	}
 	delete(p, "static")
 
-	err := l.tokenizer.Decode(static, &decode)
+	err := l.tokenizer.Decode(staticSalt, static, decode)
 	if err != nil {
-		return nil
+		return &staticToken{}
 	}
 
-	decodeFlash(decode)
-
 	return decode
 }
 
-func decodeFlash(m map[string]any) {
-	flash, ok := m["flash"]
-	if !ok {
-		return
-	}
-	delete(m, "flash")
-
-	decoded, err := base64.StdEncoding.DecodeString(flash.(string))
-	if err != nil {
-		return
-	}
-
-	var flashMap map[string]any
-	err = json.Unmarshal(decoded, &flashMap)
-	if err != nil {
-		return
-	}
-
-	m["flash"] = flashMap
-}
-
 func render404String(route Route, err error) (string, error) {
 	if errors.Is(err, NotFoundError) {
 		node, err := route.GetView().Render(nil)
@@ -32,6 +32,19 @@ type EventHandler interface {
 	Event(Socket, string, params.Params) error
 }
 
+// PageTitler is implemented by views that set the page title. The title
+// is rendered by phx.Title in the layout and updated on every render.
+type PageTitler interface {
+	PageTitle() string
+}
+
+// AsyncHandler is implemented by views handling the results of
+// Socket.StartAsync and Socket.AssignAsync. It runs in the liveview
+// goroutine and the view is rendered afterwards.
+type AsyncHandler interface {
+	HandleAsync(Socket, string, AsyncResult) error
+}
+
 type Uploader interface {
 	Uploads() *uploads.Uploads
 }
@@ -76,6 +89,14 @@ func TryEvent(a any, s Socket, event string, p params.Params) error {
 	return nil
 }
 
+func TryHandleAsync(a any, s Socket, name string, result AsyncResult) error {
+	if m, ok := a.(AsyncHandler); ok {
+		return m.HandleAsync(s, name, result)
+	}
+
+	return nil
+}
+
 func TryUploads(a any) *uploads.Uploads {
 	if m, ok := a.(Uploader); ok {
 		return m.Uploads()
@@ -83,3 +104,11 @@ func TryUploads(a any) *uploads.Uploads {
 
 	return nil
 }
+
+func TryPageTitle(a any) string {
+	if m, ok := a.(PageTitler); ok {
+		return m.PageTitle()
+	}
+
+	return ""
+}
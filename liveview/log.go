@@ -0,0 +1,49 @@
+package liveview
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-live-view/go-live-view/params"
+)
+
+// debug logs msg at the debug level with the time since start.
+func (l *lifecycle) debug(msg string, start time.Time, args ...any) {
+	if !l.logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+
+	l.logger.Debug(msg, append(args, "duration", time.Since(start))...)
+}
+
+// filtered logs p without the values of the filtered keys.
+func (l *lifecycle) filtered(p params.Params) slog.LogValuer {
+	return filteredParams{params: p, keys: l.filterParams}
+}
+
+type filteredParams struct {
+	params params.Params
+	keys   []string
+}
+
+func (f filteredParams) LogValue() slog.Value {
+	return slog.AnyValue(map[string]any(params.Filter(f.params, f.keys...)))
+}
+
+// viewType logs the type of a view.
+type viewType struct {
+	view View
+}
+
+func (v viewType) LogValue() slog.Value {
+	return slog.StringValue(fmt.Sprintf("%T", v.view))
+}
+
+// logPath leaves the query out of url, its params are logged filtered.
+func logPath(url string) string {
+	path, _, _ := strings.Cut(url, "?")
+	return path
+}
@@ -0,0 +1,113 @@
+package liveview
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/stretchr/testify/assert"
+)
+
+type loginView struct{}
+
+func (v *loginView) Event(Socket, string, params.Params) error { return nil }
+
+func (v *loginView) Render(rend.Node) (rend.Node, error) {
+	return html.Div(), nil
+}
+
+func TestEventLog(t *testing.T) {
+	tt := []struct {
+		name     string
+		opts     []LifecycleOption
+		expected map[string]any
+	}{
+		{
+			name: "default keys",
+			expected: map[string]any{
+				"email":      "ann@example.com",
+				"password":   params.Filtered,
+				"card_token": params.Filtered,
+			},
+		},
+		{
+			name: "added keys",
+			opts: []LifecycleOption{WithFilterParams("email")},
+			expected: map[string]any{
+				"email":      params.Filtered,
+				"password":   params.Filtered,
+				"card_token": params.Filtered,
+			},
+		},
+		{
+			name: "replaced keys",
+			opts: []LifecycleOption{WithOnlyFilterParams("email")},
+			expected: map[string]any{
+				"email":      params.Filtered,
+				"password":   "hunter2",
+				"card_token": "tok_1",
+			},
+		},
+		{
+			name: "no keys",
+			opts: []LifecycleOption{WithOnlyFilterParams()},
+			expected: map[string]any{
+				"email":      "ann@example.com",
+				"password":   "hunter2",
+				"card_token": "tok_1",
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			l := NewLifecycle(
+				&fakeRouter{route: &fakeRoute{view: &loginView{}}},
+				fakeTokenizer{},
+				fakeSession{},
+				append([]LifecycleOption{WithoutCSRFProtection(), WithLogger(logger)}, tc.opts...)...,
+			)
+
+			s := l.Socket(&fakeSocket{})
+
+			_, err := l.Join(s, params.Params{"url": "/login?next=/", "session": "s"})
+			assert.NoError(t, err)
+
+			buf.Reset()
+
+			_, err = l.Event(s, params.Params{
+				"event": "login",
+				"type":  "form",
+				"value": "email=ann%40example.com&password=hunter2&card_token=tok_1",
+			})
+			assert.NoError(t, err)
+
+			var logs []map[string]any
+			for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+				var entry map[string]any
+				assert.NoError(t, json.Unmarshal(line, &entry))
+				logs = append(logs, entry)
+			}
+
+			assert.Len(t, logs, 2)
+			assert.Equal(t, "liveview render", logs[0]["msg"])
+			assert.Equal(t, "liveview event", logs[1]["msg"])
+			assert.Equal(t, "*liveview.loginView", logs[1]["view"])
+			assert.Equal(t, "login", logs[1]["event"])
+			assert.Equal(t, tc.expected, logs[1]["params"])
+			assert.Contains(t, logs[1], "duration")
+		})
+	}
+}
+
+func TestLogPath(t *testing.T) {
+	assert.Equal(t, "/login", logPath("/login?token=abc"))
+	assert.Equal(t, "http://localhost/users/1", logPath("http://localhost/users/1"))
+}
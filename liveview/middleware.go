@@ -0,0 +1,90 @@
+package liveview
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-live-view/go-live-view/params"
+)
+
+// Conn is a mount of a route: either its HTTP render, with Writer and
+// Request set, or the join of its socket, with Socket set. Session holds
+// the values of the session store only, while Params also holds the query
+// string and the join payload the client controls.
+type Conn struct {
+	Writer  http.ResponseWriter
+	Request *http.Request
+	Socket  Socket
+	Params  params.Params
+	Session params.Params
+}
+
+// Connected returns true when the socket joins.
+func (c *Conn) Connected() bool {
+	return c.Socket != nil
+}
+
+// Context returns the context of the request or of the socket. Values put
+// with PutValue are seen by the next handlers.
+func (c *Conn) Context() context.Context {
+	if c.Socket != nil {
+		return c.Socket.Context()
+	}
+
+	return c.Request.Context()
+}
+
+// MountHandler mounts a route.
+type MountHandler func(*Conn) error
+
+// Middleware wraps the mount of a route, on the HTTP render and on join.
+// It calls next to go on, or halts by returning an error instead, like
+// RedirectTo or an HttpError. Values set in the Params of the Conn are
+// passed to the next handlers and to the view. Authorize from the Session,
+// never from the Params.
+//
+//	func requireUser(next lv.MountHandler) lv.MountHandler {
+//		return func(c *lv.Conn) error {
+//			if c.Session.String("user_id") == "" {
+//				return lv.RedirectTo("/login")
+//			}
+//			return next(c)
+//		}
+//	}
+type Middleware func(next MountHandler) MountHandler
+
+// RedirectError halts a mount by redirecting to URL, see RedirectTo.
+type RedirectError struct {
+	URL string
+}
+
+func (e *RedirectError) Error() string {
+	return "redirect to " + e.URL
+}
+
+// RedirectTo returns the error a Middleware halts with to redirect to url.
+// The HTTP render responds with a redirect and the socket is redirected.
+func RedirectTo(url string) error {
+	return &RedirectError{URL: url}
+}
+
+// mount runs h wrapped by the middleware of route, first one outermost.
+func mount(route Route, c *Conn, h MountHandler) error {
+	middleware := route.GetMiddleware()
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+
+	return h(c)
+}
+
+// redirectURL returns the URL err redirects to.
+func redirectURL(err error) (string, bool) {
+	var redirect *RedirectError
+	if !errors.As(err, &redirect) {
+		return "", false
+	}
+
+	return redirect.URL, true
+}
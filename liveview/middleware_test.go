@@ -0,0 +1,253 @@
+package liveview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRouter struct {
+	route Route
+}
+
+func (r *fakeRouter) GetRoute(string) (Route, error) { return r.route, nil }
+func (r *fakeRouter) Routable(Route, Route) bool     { return true }
+func (r *fakeRouter) GetLayout() func(...rend.Node) rend.Node {
+	return func(children ...rend.Node) rend.Node {
+		return html.Div(children...)
+	}
+}
+
+type fakeSession struct{}
+
+func (fakeSession) Get(*http.Request) map[string]any {
+	return map[string]any{}
+}
+
+type mountView struct {
+	calls *[]string
+	user  string
+	value any
+}
+
+func (v *mountView) Mount(s Socket, p params.Params) error {
+	*v.calls = append(*v.calls, "mount")
+	v.user = p.String("user")
+
+	if s != nil {
+		v.value = s.Context().Value(userKey{})
+	}
+
+	return nil
+}
+
+func (v *mountView) Render(rend.Node) (rend.Node, error) {
+	return html.Div(html.Text(v.user)), nil
+}
+
+func tracing(name string, calls *[]string) Middleware {
+	return func(next MountHandler) MountHandler {
+		return func(c *Conn) error {
+			*calls = append(*calls, name+">")
+			err := next(c)
+			*calls = append(*calls, "<"+name)
+			return err
+		}
+	}
+}
+
+func newMiddlewareLifecycle(view View, middleware ...Middleware) *lifecycle {
+	return NewLifecycle(
+		&fakeRouter{route: &fakeRoute{view: view, middleware: middleware}},
+		fakeTokenizer{},
+		fakeSession{},
+		WithoutCSRFProtection(),
+	)
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	view := &mountView{calls: &calls}
+
+	l := newMiddlewareLifecycle(view, tracing("a", &calls), tracing("b", &calls))
+
+	_, err := l.StaticRender(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a>", "b>", "mount", "<b", "<a"}, calls)
+
+	calls = nil
+
+	_, err = l.Join(l.Socket(&fakeSocket{}), params.Params{"url": "/", "session": "s"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a>", "b>", "mount", "<b", "<a"}, calls)
+}
+
+func TestMiddlewareValues(t *testing.T) {
+	var calls []string
+	view := &mountView{calls: &calls}
+
+	l := newMiddlewareLifecycle(view, func(next MountHandler) MountHandler {
+		return func(c *Conn) error {
+			c.Params["user"] = "ann"
+			PutValue(c.Context(), userKey{}, "ann")
+			return next(c)
+		}
+	})
+
+	resp, err := l.StaticRender(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.NoError(t, err)
+	assert.Contains(t, resp, "ann")
+
+	s, done := l.Begin(&fakeSocket{})
+	defer done()
+
+	_, err = l.Join(s, params.Params{"url": "/", "session": "s"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ann", view.user)
+	assert.Equal(t, "ann", view.value)
+}
+
+func TestMiddlewareSession(t *testing.T) {
+	tt := []struct {
+		name    string
+		session string
+		user    string
+	}{
+		{
+			name:    "user of the session",
+			session: `{"v":{"user_id":"ann"}}`,
+			user:    "ann",
+		},
+		{
+			name:    "params are not the session",
+			session: `{"v":{}}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var session, param string
+
+			view := &mountView{calls: &[]string{}}
+			route := &fakeRoute{view: view, middleware: []Middleware{
+				func(next MountHandler) MountHandler {
+					return func(c *Conn) error {
+						session = c.Session.String("user_id")
+						param = c.Params.String("user_id")
+						return next(c)
+					}
+				},
+			}}
+
+			l := NewLifecycle(&fakeRouter{route: route}, jsonTokenizer{}, fakeSession{}, WithoutCSRFProtection())
+
+			_, err := l.Join(l.Socket(&fakeSocket{}), params.Params{
+				"url":     "/",
+				"session": tc.session,
+				"user_id": "bob",
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.user, session)
+			assert.NotEmpty(t, param)
+		})
+	}
+}
+
+func TestMiddlewareHalt(t *testing.T) {
+	redirect := func(next MountHandler) MountHandler {
+		return func(c *Conn) error {
+			return RedirectTo("/login")
+		}
+	}
+
+	forbidden := func(next MountHandler) MountHandler {
+		return func(c *Conn) error {
+			return NewHttpError(http.StatusForbidden, "forbidden")
+		}
+	}
+
+	t.Run("redirect on render", func(t *testing.T) {
+		var calls []string
+		l := newMiddlewareLifecycle(&mountView{calls: &calls}, redirect)
+
+		w := httptest.NewRecorder()
+		resp, err := l.StaticRender(w, httptest.NewRequest("GET", "/", nil))
+		assert.NoError(t, err)
+		assert.Empty(t, resp)
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "/login", w.Header().Get("Location"))
+		assert.Empty(t, calls)
+	})
+
+	t.Run("redirect on join", func(t *testing.T) {
+		var calls []string
+		l := newMiddlewareLifecycle(&mountView{calls: &calls}, redirect)
+
+		fake := &fakeSocket{}
+		root, err := l.Join(l.Socket(fake), params.Params{"url": "/", "session": "s"})
+		assert.NoError(t, err)
+		assert.Nil(t, root)
+		assert.Empty(t, calls)
+
+		assert.Len(t, fake.pushes, 1)
+		assert.Equal(t, "redirect", fake.pushes[0].event)
+		assert.Equal(t, "/login", fake.pushes[0].payload.(map[string]any)["to"])
+	})
+
+	t.Run("http error", func(t *testing.T) {
+		var calls []string
+		l := newMiddlewareLifecycle(&mountView{calls: &calls}, forbidden)
+
+		_, err := l.StaticRender(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+		httpErr, ok := err.(HttpError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusForbidden, httpErr.Code())
+		assert.Empty(t, calls)
+	})
+}
+
+func TestMiddlewareRunsOnNavigate(t *testing.T) {
+	var calls []string
+	view := &mountView{calls: &calls}
+
+	l := newMiddlewareLifecycle(view, tracing("a", &calls))
+
+	_, err := l.Join(l.Socket(&fakeSocket{}), params.Params{"url": "/", "session": "s"})
+	assert.NoError(t, err)
+
+	// a live navigation joins again.
+	_, err = l.Join(l.Socket(&fakeSocket{}), params.Params{"redirect": "/other", "session": "s"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"a>", "mount", "<a", "a>", "mount", "<a"}, calls)
+}
+
+func TestRouteLayout(t *testing.T) {
+	var calls []string
+
+	route := &fakeRoute{
+		view: &mountView{calls: &calls},
+		layout: func(children ...rend.Node) rend.Node {
+			return html.Main(children...)
+		},
+	}
+
+	l := NewLifecycle(&fakeRouter{route: route}, fakeTokenizer{}, fakeSession{}, WithoutCSRFProtection())
+
+	resp, err := l.StaticRender(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.NoError(t, err)
+	assert.Contains(t, resp, "<main")
+
+	route.layout = nil
+
+	resp, err = l.StaticRender(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.NoError(t, err)
+	assert.NotContains(t, resp, "<main")
+	assert.Contains(t, resp, "<div")
+}
@@ -0,0 +1,50 @@
+package liveview
+
+import "sync"
+
+// Navigation carries the route of the liveview a client navigates away
+// from to the liveview it joins next. Every join gets its own lifecycle,
+// share one Navigation per connection with WithNavigation.
+type Navigation struct {
+	mu     sync.Mutex
+	route  Route
+	joined bool
+}
+
+func NewNavigation() *Navigation {
+	return &Navigation{}
+}
+
+// WithNavigation shares n with the other lifecycles of the connection.
+func WithNavigation(n *Navigation) LifecycleOption {
+	return func(l *lifecycle) {
+		l.nav = n
+	}
+}
+
+// current returns the route of the last join or patch.
+func (n *Navigation) current() Route {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.route
+}
+
+func (n *Navigation) set(route Route) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.route = route
+}
+
+// first reports whether it is called for the first join of the
+// connection.
+func (n *Navigation) first() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	first := !n.joined
+	n.joined = true
+
+	return first
+}
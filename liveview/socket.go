@@ -1,10 +1,15 @@
 package liveview
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-live-view/go-live-view/channel"
+	"github.com/go-live-view/go-live-view/params"
 )
 
 var _ Socket = (*socket)(nil)
@@ -13,29 +18,79 @@ type redirectOption func(map[string]any)
 
 type Socket interface {
 	channel.Socket
+	Context() context.Context
 	PushEvent(string, any) error
 	PushPatch(string, ...redirectOption) error
+	PushPatchParams(string, params.Params, ...redirectOption) error
+	URL(string, params.Params) (string, error)
 	PushNavigate(string, ...redirectOption) error
 	Redirect(string, ...redirectOption) error
 	Redirected() bool
+	PutFlash(string, string)
+	ClearFlash(...string)
+	Flash() Flash
+	SetPageTitle(string)
+	SendUpdate(string, params.Params) error
+	StartAsync(string, func(context.Context) (any, error))
+	AssignAsync(string, AsyncAssign, func(context.Context) (any, error))
+	CancelAsync(string)
+	SendAfter(time.Duration, string, any) TimerRef
+	Interval(time.Duration, string) TimerRef
+	CancelTimer(TimerRef) bool
+}
+
+// state is shared by the sockets of a liveview across messages.
+type state struct {
+	ctx       *valuesContext
+	flash     Flash
+	pageTitle string
+	async     *asyncTasks
+	timers    *timers
+}
+
+func newState() *state {
+	return &state{
+		ctx:    newValuesContext(context.Background()),
+		flash:  Flash{},
+		async:  newAsyncTasks(),
+		timers: newTimers(),
+	}
 }
 
 type socket struct {
 	channel.Socket
-	redirected bool
+	ctx         context.Context
+	redirected  bool
+	router      Router
+	state       *state
+	encodeFlash func(Flash) (string, error)
 }
 
 func NewSocket(s channel.Socket) *socket {
 	return &socket{
 		Socket: s,
+		state:  newState(),
+		encodeFlash: func(f Flash) (string, error) {
+			js, err := json.Marshal(f)
+			if err != nil {
+				return "", err
+			}
+
+			return base64.StdEncoding.EncodeToString(js), nil
+		},
 	}
 }
 
-func WithFlash(key, value string) redirectOption {
+// WithFlash puts a flash message before redirecting.
+func WithFlash(kind, msg string) redirectOption {
 	return func(m map[string]any) {
-		m["flash"] = map[string]string{
-			key: value,
+		flash, ok := m["flash"].(Flash)
+		if !ok {
+			flash = Flash{}
+			m["flash"] = flash
 		}
+
+		flash[kind] = msg
 	}
 }
 
@@ -67,6 +122,118 @@ func (s *socket) PushBroadcast(event string, payload any) error {
 	)
 }
 
+// Broadcast sends payload to all liveviews subscribed to topic.
+func (s *socket) Broadcast(topic, event string, payload any) error {
+	return s.Socket.Broadcast(topic, "event",
+		map[string]any{
+			"event": event,
+			"type":  "broadcast",
+			"value": payload,
+		},
+	)
+}
+
+// SendUpdate updates the live component with id with assigns and
+// re-renders. It is safe to call outside of the component's parent.
+func (s *socket) SendUpdate(id string, assigns params.Params) error {
+	return s.Socket.PushSelf("send_update", map[string]any{
+		"id":      id,
+		"assigns": map[string]any(assigns),
+	})
+}
+
+// Context returns the context of the message being handled. It is
+// cancelled once the message is handled or the client disconnects, and
+// carries the values added with PutValue.
+func (s *socket) Context() context.Context {
+	if s.ctx == nil {
+		return s.state.ctx
+	}
+
+	return s.ctx
+}
+
+// StartAsync runs f in a new goroutine and hands its result to the
+// HandleAsync callback of the view. The context is cancelled when the
+// liveview leaves or disconnects, or when another function is started
+// under name. It carries the values of the liveview context.
+func (s *socket) StartAsync(name string, f func(context.Context) (any, error)) {
+	s.startAsync(name, nil, f)
+}
+
+// AssignAsync is like StartAsync, but first sets the result on a. Since it
+// happens in the liveview goroutine, events never see a half-applied result.
+func (s *socket) AssignAsync(name string, a AsyncAssign, f func(context.Context) (any, error)) {
+	s.startAsync(name, a, f)
+}
+
+// CancelAsync cancels the function started under name. Its result is
+// dropped.
+func (s *socket) CancelAsync(name string) {
+	s.state.async.cancel(name)
+}
+
+func (s *socket) startAsync(name string, a AsyncAssign, f func(context.Context) (any, error)) {
+	ctx, id := s.state.async.start(s.state.ctx, name, a)
+
+	go func() {
+		value, err := runAsync(ctx, f)
+
+		// delivered through the mailbox, dropped once the liveview is gone.
+		s.Socket.PushSelf("async_result", map[string]any{
+			"name": name,
+			"id":   id,
+			"result": AsyncResult{
+				Value: value,
+				Err:   err,
+			},
+		})
+	}()
+}
+
+// SendAfter sends event with payload to the view's Event handler after d,
+// like PushSelf. The timer is stopped when the liveview leaves.
+func (s *socket) SendAfter(d time.Duration, event string, payload any) TimerRef {
+	ref := s.state.timers.newRef()
+
+	t := time.AfterFunc(d, func() {
+		s.pushTimer(ref, event, payload)
+	})
+
+	s.state.timers.add(ref, func() { t.Stop() }, false)
+
+	return ref
+}
+
+// Interval sends event to the view's Event handler every d until the timer
+// is cancelled or the liveview leaves.
+func (s *socket) Interval(d time.Duration, event string) TimerRef {
+	ref := s.state.timers.newRef()
+
+	stop := interval(d, func() {
+		s.pushTimer(ref, event, nil)
+	})
+
+	s.state.timers.add(ref, stop, true)
+
+	return ref
+}
+
+// CancelTimer stops the timer. Events it already sent are dropped. It
+// returns false if the timer was not running.
+func (s *socket) CancelTimer(ref TimerRef) bool {
+	return s.state.timers.cancel(ref)
+}
+
+func (s *socket) pushTimer(ref TimerRef, event string, payload any) {
+	s.Socket.PushSelf("timer", map[string]any{
+		"ref":   int64(ref),
+		"event": event,
+		"type":  "self",
+		"value": payload,
+	})
+}
+
 // PushEvent sends an event to the client.
 func (s *socket) PushEvent(event string, payload any) error {
 	return s.Push("e", [][]any{
@@ -87,6 +254,8 @@ func (s *socket) PushPatch(url string, opts ...redirectOption) error {
 		opt(payload)
 	}
 
+	s.takeFlash(payload)
+
 	err := s.Push("live_patch", payload)
 	if err != nil {
 		return err
@@ -94,9 +263,8 @@ func (s *socket) PushPatch(url string, opts ...redirectOption) error {
 
 	// client does not return an event, so we push it to ourselves
 	err = s.Socket.PushSelf("live_patch", map[string]any{
-		"kind":  payload["kind"],
-		"url":   payload["to"],
-		"flash": payload["flash"],
+		"kind": payload["kind"],
+		"url":  payload["to"],
 	})
 	if err != nil {
 		return err
@@ -107,8 +275,47 @@ func (s *socket) PushPatch(url string, opts ...redirectOption) error {
 	return nil
 }
 
-// PushNavigate sends a live_redirect to the client.
-func (s socket) PushNavigate(url string, opts ...redirectOption) error {
+// PushPatchParams sends a live_patch to path with p encoded as its query
+// string, see params.EncodeQuery. A query already in path is kept, with
+// the keys of p replacing its own.
+func (s *socket) PushPatchParams(path string, p params.Params, opts ...redirectOption) error {
+	path, fragment, hasFragment := strings.Cut(path, "#")
+	path, rawQuery, _ := strings.Cut(path, "?")
+
+	query, err := params.ParseQuery(rawQuery)
+	if err != nil {
+		return fmt.Errorf("liveview: patch %s: %w", path, err)
+	}
+
+	for k, v := range p {
+		query[k] = v
+	}
+
+	if encoded := params.EncodeQuery(query); encoded != "" {
+		path += "?" + encoded
+	}
+
+	if hasFragment {
+		path += "#" + fragment
+	}
+
+	return s.PushPatch(path, opts...)
+}
+
+// URL returns the URL of the route named name, filling its params from p,
+// see router.WithName.
+func (s *socket) URL(name string, p params.Params) (string, error) {
+	b, ok := s.router.(URLBuilder)
+	if !ok {
+		return "", fmt.Errorf("liveview: router cannot build the url of %s", name)
+	}
+
+	return b.URL(name, p)
+}
+
+// PushNavigate sends a live_redirect to the client. The flash is handed
+// to the liveview mounted next.
+func (s *socket) PushNavigate(url string, opts ...redirectOption) error {
 	payload := map[string]any{
 		"to":   url,
 		"kind": "push",
@@ -118,7 +325,12 @@ func (s socket) PushNavigate(url string, opts ...redirectOption) error {
 		opt(payload)
 	}
 
-	err := s.Push("live_redirect", payload)
+	err := s.carryFlash(payload)
+	if err != nil {
+		return err
+	}
+
+	err = s.Push("live_redirect", payload)
 	if err != nil {
 		return err
 	}
@@ -128,8 +340,9 @@ func (s socket) PushNavigate(url string, opts ...redirectOption) error {
 	return nil
 }
 
-// Redirect sends a redirect to the client.
-func (s socket) Redirect(url string, opts ...redirectOption) error {
+// Redirect sends a redirect to the client. The client stores the flash in
+// the __phoenix_flash__ cookie for the next page to render it.
+func (s *socket) Redirect(url string, opts ...redirectOption) error {
 	payload := map[string]any{
 		"to": url,
 	}
@@ -138,9 +351,12 @@ func (s socket) Redirect(url string, opts ...redirectOption) error {
 		opt(payload)
 	}
 
-	encodeFlash(payload)
+	err := s.carryFlash(payload)
+	if err != nil {
+		return err
+	}
 
-	err := s.Push("redirect", payload)
+	err = s.Push("redirect", payload)
 	if err != nil {
 		return err
 	}
@@ -155,17 +371,62 @@ func (s *socket) Redirected() bool {
 	return s.redirected
 }
 
-func encodeFlash(m map[string]any) {
-	flash, ok := m["flash"]
-	if !ok {
+// PutFlash sets the flash message of kind.
+func (s *socket) PutFlash(kind, msg string) {
+	s.state.flash[kind] = msg
+}
+
+// ClearFlash removes the flash messages of the given kinds, or all of them
+// when no kind is given.
+func (s *socket) ClearFlash(kinds ...string) {
+	if len(kinds) == 0 {
+		clear(s.state.flash)
 		return
 	}
-	delete(m, "flash")
 
-	js, err := json.Marshal(flash)
+	for _, kind := range kinds {
+		delete(s.state.flash, kind)
+	}
+}
+
+// Flash returns the flash of the liveview. The map is updated in place,
+// views can keep it to render it.
+func (s *socket) Flash() Flash {
+	return s.state.flash
+}
+
+// SetPageTitle sets the title of the page. It takes precedence over the
+// title returned by a PageTitler view until the next live navigation.
+func (s *socket) SetPageTitle(title string) {
+	s.state.pageTitle = title
+}
+
+// takeFlash moves the flash set with WithFlash from the payload to the
+// socket.
+func (s *socket) takeFlash(payload map[string]any) {
+	if flash, ok := payload["flash"].(Flash); ok {
+		for kind, msg := range flash {
+			s.PutFlash(kind, msg)
+		}
+	}
+
+	delete(payload, "flash")
+}
+
+// carryFlash encodes the flash of the socket into the payload.
+func (s *socket) carryFlash(payload map[string]any) error {
+	s.takeFlash(payload)
+
+	if len(s.state.flash) == 0 {
+		return nil
+	}
+
+	flash, err := s.encodeFlash(s.state.flash)
 	if err != nil {
-		return
+		return err
 	}
 
-	m["flash"] = base64.StdEncoding.EncodeToString(js)
+	payload["flash"] = flash
+
+	return nil
 }
@@ -0,0 +1,99 @@
+package liveview
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// TimerRef identifies a timer started with Socket.SendAfter or
+// Socket.Interval.
+type TimerRef int64
+
+// lastTimerRef makes refs unique across liveviews: the timer events of a
+// crashed liveview reach the one rejoining its topic, which must not take
+// them for its own.
+var lastTimerRef atomic.Int64
+
+// timers tracks the running timers of a liveview. They are only touched
+// in the liveview goroutine, the timer goroutines just send messages.
+type timers struct {
+	stops map[TimerRef]timer
+}
+
+type timer struct {
+	stop   func()
+	repeat bool
+}
+
+func newTimers() *timers {
+	return &timers{
+		stops: map[TimerRef]timer{},
+	}
+}
+
+func (t *timers) newRef() TimerRef {
+	return TimerRef(lastTimerRef.Add(1))
+}
+
+func (t *timers) add(ref TimerRef, stop func(), repeat bool) {
+	t.stops[ref] = timer{
+		stop:   stop,
+		repeat: repeat,
+	}
+}
+
+// fire returns whether the timer is still running, forgetting it unless
+// it repeats.
+func (t *timers) fire(ref TimerRef) bool {
+	tm, ok := t.stops[ref]
+	if ok && !tm.repeat {
+		delete(t.stops, ref)
+	}
+
+	return ok
+}
+
+func (t *timers) cancel(ref TimerRef) bool {
+	tm, ok := t.stops[ref]
+	if !ok {
+		return false
+	}
+
+	tm.stop()
+	delete(t.stops, ref)
+
+	return true
+}
+
+func (t *timers) cancelAll() {
+	for ref := range t.stops {
+		t.cancel(ref)
+	}
+}
+
+// interval sends a tick every d until stop is called. stop returns once
+// the last tick was sent.
+func interval(d time.Duration, tick func()) (stop func()) {
+	ticker := time.NewTicker(d)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				tick()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
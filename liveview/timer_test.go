@@ -0,0 +1,113 @@
+package liveview
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/stretchr/testify/assert"
+)
+
+type timerView struct {
+	asyncView
+	events []string
+}
+
+func (v *timerView) Event(s Socket, event string, p params.Params) error {
+	v.events = append(v.events, event)
+	return nil
+}
+
+func TestSendAfter(t *testing.T) {
+	view := &timerView{}
+	l, fake := newAsyncLifecycle(view)
+	s := l.Socket(fake)
+
+	s.SendAfter(time.Millisecond, "tick", nil)
+
+	_, err := l.Timer(s, <-fake.self)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tick"}, view.events)
+	assert.Empty(t, l.state.timers.stops)
+}
+
+func TestInterval(t *testing.T) {
+	view := &timerView{}
+	l, fake := newAsyncLifecycle(view)
+	s := l.Socket(fake)
+
+	ref := s.Interval(time.Millisecond, "tick")
+
+	for range 3 {
+		_, err := l.Timer(s, <-fake.self)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{"tick", "tick", "tick"}, view.events)
+
+	assert.True(t, s.CancelTimer(ref))
+	assert.False(t, s.CancelTimer(ref))
+}
+
+func TestTimerRefsAreUnique(t *testing.T) {
+	view := &timerView{}
+	l, fake := newAsyncLifecycle(view)
+	s := l.Socket(fake)
+
+	other := &timerView{}
+	ol, ofake := newAsyncLifecycle(other)
+	osock := ol.Socket(ofake)
+
+	ref := s.Interval(time.Millisecond, "tick")
+	oref := osock.Interval(time.Hour, "tick")
+	assert.NotEqual(t, ref, oref)
+
+	// a late tick of a liveview that left reaches the one rejoining.
+	p := <-fake.self
+	assert.NoError(t, l.Leave())
+
+	diff, err := ol.Timer(osock, p)
+	assert.NoError(t, err)
+	assert.Nil(t, diff)
+	assert.Empty(t, other.events)
+
+	assert.NoError(t, ol.Leave())
+}
+
+func TestTimersCancelled(t *testing.T) {
+	tt := []struct {
+		name   string
+		cancel func(*lifecycle, Socket, TimerRef)
+	}{
+		{
+			name: "cancel timer",
+			cancel: func(l *lifecycle, s Socket, ref TimerRef) {
+				s.CancelTimer(ref)
+			},
+		},
+		{
+			name: "leave",
+			cancel: func(l *lifecycle, s Socket, ref TimerRef) {
+				assert.NoError(t, l.Leave())
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			view := &timerView{}
+			l, fake := newAsyncLifecycle(view)
+			s := l.Socket(fake)
+
+			ref := s.SendAfter(0, "tick", nil)
+
+			// the event is already queued when the timer is cancelled
+			p := <-fake.self
+			tc.cancel(l, s, ref)
+
+			diff, err := l.Timer(s, p)
+			assert.NoError(t, err)
+			assert.Nil(t, diff)
+			assert.Empty(t, view.events)
+		})
+	}
+}
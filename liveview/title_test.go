@@ -0,0 +1,49 @@
+package liveview
+
+import (
+	"testing"
+
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+	"github.com/stretchr/testify/assert"
+)
+
+// pathRouter routes by exact path.
+type pathRouter struct {
+	fakeRouter
+	routes map[string]Route
+}
+
+func (r *pathRouter) GetRoute(path string) (Route, error) { return r.routes[path], nil }
+
+// titleSetter sets the title from Mount.
+type titleSetter struct{}
+
+func (v *titleSetter) Mount(s Socket, _ params.Params) error {
+	s.SetPageTitle("3 unread")
+	return nil
+}
+
+func (v *titleSetter) Render(rend.Node) (rend.Node, error) { return html.Div(), nil }
+
+type titledView struct{}
+
+func (v *titledView) PageTitle() string                   { return "Settings" }
+func (v *titledView) Render(rend.Node) (rend.Node, error) { return html.Div(), nil }
+
+func TestPageTitleOnNavigate(t *testing.T) {
+	l := NewLifecycle(&pathRouter{routes: map[string]Route{
+		"/inbox":    &fakeRoute{view: &titleSetter{}},
+		"/settings": &fakeRoute{view: &titledView{}},
+	}}, fakeTokenizer{}, fakeSession{}, WithoutCSRFProtection())
+
+	root, err := l.Join(l.Socket(&fakeSocket{}), params.Params{"url": "/inbox", "session": "s"})
+	assert.NoError(t, err)
+	assert.Equal(t, "3 unread", root.Title)
+
+	// a live navigation joins again.
+	root, err = l.Join(l.Socket(&fakeSocket{}), params.Params{"redirect": "/settings", "session": "s"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Settings", root.Title)
+}
@@ -0,0 +1,40 @@
+package liveview
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// jsonTokenizer encodes tokens as plain JSON.
+type jsonTokenizer struct{}
+
+func (jsonTokenizer) Encode(salt string, v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func (jsonTokenizer) Decode(salt, token string, v any) error {
+	return json.Unmarshal([]byte(token), v)
+}
+
+func TestDecodeUploadTokenOwner(t *testing.T) {
+	owner := NewLifecycle(nil, jsonTokenizer{}, nil)
+	other := NewLifecycle(nil, jsonTokenizer{}, nil)
+
+	token, err := owner.tokenizer.Encode(uploadSalt, &uploadToken{
+		LiveView:  owner.id,
+		ConfigRef: "avatar",
+		Ref:       "0",
+	})
+	assert.NoError(t, err)
+
+	configRef, ref, err := owner.DecodeUploadToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "avatar", configRef)
+	assert.Equal(t, "0", ref)
+
+	_, _, err = other.DecodeUploadToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
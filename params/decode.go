@@ -0,0 +1,345 @@
+package params
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DecodeOption configures Decode and Validate.
+type DecodeOption func(*decoder)
+
+type decoder struct {
+	errs       Errors
+	validators map[string]func(any) error
+}
+
+// WithValidator registers a validator used by the validate tags naming
+// it. It returns an error for invalid values of the fields.
+func WithValidator(name string, f func(any) error) DecodeOption {
+	return func(d *decoder) {
+		d.validators[name] = f
+	}
+}
+
+func newDecoder(opts ...DecodeOption) *decoder {
+	d := &decoder{
+		errs:       Errors{},
+		validators: map[string]func(any) error{},
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Decode sets the fields of the struct dst points to from p. A field is
+// read from the key in its param tag, or from its name compared case
+// insensitively. Fields tagged param:"-" and keys without a field are
+// ignored. Strings are converted to the field's type, so values of forms
+// decode into numbers and booleans.
+//
+// Keys in the form encoding, like user[address][city] or tags[], decode
+// into nested structs and slices. Slices also decode from maps keyed by
+// index, as sent for nested inputs.
+//
+// Decoded fields are then checked against their validate tag, see
+// Validate. All invalid fields are reported in the returned Errors.
+func Decode(p Params, dst any, opts ...DecodeOption) error {
+	if pp, ok := dst.(*Params); ok {
+		*pp = Expand(p)
+		return nil
+	}
+
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("params: decode into %T, want a pointer to a struct", dst)
+	}
+
+	d := newDecoder(opts...)
+	d.decodeStruct("", Expand(p), v.Elem())
+
+	return d.err()
+}
+
+// Validate checks the fields of the struct dst points to against their
+// validate tag, a comma separated list of:
+//
+//   - required: the value is not zero, e.g. not empty
+//   - min=n, max=n: bounds of numbers, or of the length of strings and slices
+//   - the name of a validator registered with WithValidator
+//
+// Strings are also matched against the regular expression in their
+// pattern tag. Zero values only fail the required rule, so optional fields
+// can be left empty.
+func Validate(dst any, opts ...DecodeOption) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("params: validate %T, want a struct", dst)
+	}
+
+	d := newDecoder(opts...)
+	d.validateStruct("", v)
+
+	return d.err()
+}
+
+func (d *decoder) err() error {
+	if len(d.errs) == 0 {
+		return nil
+	}
+
+	return d.errs
+}
+
+func (d *decoder) decodeStruct(prefix string, p Params, v reflect.Value) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, raw, ok := lookupField(p, field)
+		if name == "-" {
+			continue
+		}
+
+		path := fieldPath(prefix, name)
+
+		if !ok {
+			// missing fields are still validated, e.g. when required.
+			d.validate(path, field, v.Field(i), false)
+
+			if fv := reflect.Indirect(v.Field(i)); fv.Kind() == reflect.Struct {
+				d.validateStruct(path, fv)
+			}
+			continue
+		}
+
+		err := d.decodeValue(path, raw, v.Field(i))
+		if err != nil {
+			d.errs.Add(path, err.Error())
+			continue
+		}
+
+		d.validate(path, field, v.Field(i), true)
+	}
+}
+
+func (d *decoder) validateStruct(prefix string, v reflect.Value) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("param") == "-" {
+			continue
+		}
+
+		name := fieldName(field)
+		path := fieldPath(prefix, name)
+
+		// without params, only set fields count as present.
+		d.validate(path, field, v.Field(i), !v.Field(i).IsZero())
+
+		fv := reflect.Indirect(v.Field(i))
+		if fv.Kind() == reflect.Struct {
+			d.validateStruct(path, fv)
+		}
+	}
+}
+
+// lookupField returns the name and raw value of field in p.
+func lookupField(p Params, field reflect.StructField) (string, any, bool) {
+	name, tagged := field.Tag.Lookup("param")
+	if name == "-" {
+		return name, nil, false
+	}
+
+	if tagged {
+		raw, ok := p[name]
+		return name, raw, ok
+	}
+
+	if raw, ok := p[field.Name]; ok {
+		return field.Name, raw, true
+	}
+
+	for k, raw := range p {
+		if strings.EqualFold(k, field.Name) {
+			return k, raw, true
+		}
+	}
+
+	return field.Name, nil, false
+}
+
+func fieldName(field reflect.StructField) string {
+	if name, ok := field.Tag.Lookup("param"); ok {
+		return name
+	}
+
+	return field.Name
+}
+
+// fieldPath returns the path of a field in the form encoding.
+func fieldPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "[" + name + "]"
+}
+
+func (d *decoder) decodeValue(path string, raw any, v reflect.Value) error {
+	if raw == nil {
+		v.SetZero()
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decodeValue(path, raw, v.Elem())
+	case reflect.Struct:
+		d.decodeStruct(path, Params{"v": raw}.Map("v"), v)
+		return nil
+	case reflect.Slice:
+		items := sliceItems(raw)
+
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			itemPath := fieldPath(path, strconv.Itoa(i))
+
+			err := d.decodeValue(itemPath, item, s.Index(i))
+			if err != nil {
+				d.errs.Add(itemPath, err.Error())
+			}
+		}
+		v.Set(s)
+
+		return nil
+	case reflect.Interface:
+		rv := reflect.ValueOf(raw)
+		if !rv.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("cannot assign %T", raw)
+		}
+		v.Set(rv)
+
+		return nil
+	}
+
+	s, err := scalar(raw)
+	if err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			// checkboxes send "on" when checked
+			b = s == "on"
+			if !b && s != "" && s != "off" {
+				return fmt.Errorf("invalid boolean %q", s)
+			}
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", s)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// sliceItems returns the items of a list, a map keyed by index, or a
+// single value.
+func sliceItems(raw any) []any {
+	switch v := raw.(type) {
+	case []any:
+		return v
+	case []string:
+		items := make([]any, len(v))
+		for i := range v {
+			items[i] = v[i]
+		}
+		return items
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+
+		sort.Slice(keys, func(i, j int) bool {
+			a, errA := strconv.Atoi(keys[i])
+			b, errB := strconv.Atoi(keys[j])
+			if errA != nil || errB != nil {
+				return keys[i] < keys[j]
+			}
+			return a < b
+		})
+
+		items := make([]any, len(keys))
+		for i, k := range keys {
+			items[i] = v[k]
+		}
+		return items
+	default:
+		return []any{raw}
+	}
+}
+
+// scalar returns the string form of the values JSON and forms produce.
+func scalar(raw any) (string, error) {
+	switch v := raw.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []any:
+		// repeated keys, like the hidden input of a checkbox, last one wins.
+		if len(v) > 0 {
+			return scalar(v[len(v)-1])
+		}
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported value %T", raw)
+	}
+}
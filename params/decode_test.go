@@ -0,0 +1,241 @@
+package params
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type decodeAddress struct {
+	City string `param:"city"`
+}
+
+type decodeUser struct {
+	Name    string `param:"name"`
+	Age     int    `param:"age"`
+	Score   float64
+	Admin   bool           `param:"admin"`
+	Tags    []string       `param:"tags"`
+	Address *decodeAddress `param:"address"`
+	Ignored string         `param:"-"`
+	hidden  string
+}
+
+func TestDecode(t *testing.T) {
+	tt := []struct {
+		name     string
+		input    Params
+		expected decodeUser
+		err      string
+	}{
+		{
+			name: "form strings",
+			input: Params{
+				"name":  "john",
+				"age":   "42",
+				"score": "1.5",
+				"admin": "on",
+			},
+			expected: decodeUser{Name: "john", Age: 42, Score: 1.5, Admin: true},
+		},
+		{
+			name: "json values",
+			input: Params{
+				"name":  "john",
+				"age":   float64(42),
+				"Score": float64(1.5),
+				"admin": true,
+				"tags":  []any{"a", "b"},
+			},
+			expected: decodeUser{Name: "john", Age: 42, Score: 1.5, Admin: true, Tags: []string{"a", "b"}},
+		},
+		{
+			name: "nested struct",
+			input: Params{
+				"address": map[string]any{"city": "Paris"},
+			},
+			expected: decodeUser{Address: &decodeAddress{City: "Paris"}},
+		},
+		{
+			name: "ignored fields",
+			input: Params{
+				"Ignored": "x",
+				"hidden":  "x",
+				"unknown": "x",
+			},
+			expected: decodeUser{},
+		},
+		{
+			name:  "invalid integer",
+			input: Params{"age": "old"},
+			err:   `params: age: invalid integer "old"`,
+		},
+		{
+			name:  "invalid boolean",
+			input: Params{"admin": "maybe"},
+			err:   `params: admin: invalid boolean "maybe"`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var u decodeUser
+
+			err := Decode(tc.input, &u)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, u)
+		})
+	}
+}
+
+func TestDecodeParams(t *testing.T) {
+	var p Params
+
+	err := Decode(Params{"a": "b"}, &p)
+	assert.NoError(t, err)
+	assert.Equal(t, Params{"a": "b"}, p)
+}
+
+func TestDecodeInvalidDestination(t *testing.T) {
+	var s string
+
+	assert.Error(t, Decode(Params{}, &s))
+	assert.Error(t, Decode(Params{}, decodeUser{}))
+}
+
+type decodeItem struct {
+	Name string `param:"name" validate:"required"`
+}
+
+type decodeOrder struct {
+	User struct {
+		Email   string        `param:"email" validate:"required" pattern:"^[^@]+@[^@]+$"`
+		Address decodeAddress `param:"address"`
+	} `param:"user"`
+	Items    []decodeItem `param:"items"`
+	Tags     []string     `param:"tags" validate:"max=2"`
+	Quantity int          `param:"quantity" validate:"min=1,max=10"`
+	Code     string       `param:"code" validate:"min=3,upper"`
+}
+
+func upper(v any) error {
+	if s := v.(string); s != strings.ToUpper(s) {
+		return errors.New("must be upper case")
+	}
+
+	return nil
+}
+
+func TestDecodeForm(t *testing.T) {
+	var o decodeOrder
+
+	err := Decode(Params{
+		"user[email]":         "john@example.com",
+		"user[address][city]": "Paris",
+		"items[1][name]":      "b",
+		"items[0][name]":      "a",
+		"tags[]":              []any{"x", "y"},
+		"quantity":            "2",
+		"code":                "ABC",
+	}, &o, WithValidator("upper", upper))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "john@example.com", o.User.Email)
+	assert.Equal(t, "Paris", o.User.Address.City)
+	assert.Equal(t, []decodeItem{{"a"}, {"b"}}, o.Items)
+	assert.Equal(t, []string{"x", "y"}, o.Tags)
+	assert.Equal(t, 2, o.Quantity)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tt := []struct {
+		name     string
+		input    Params
+		expected Errors
+	}{
+		{
+			name:  "missing required fields",
+			input: Params{"quantity": "1"},
+			expected: Errors{
+				"user[email]": {"is required"},
+			},
+		},
+		{
+			name: "all invalid fields are reported",
+			input: Params{
+				"user[email]":    "john",
+				"items[0][name]": "",
+				"tags[]":         []any{"a", "b", "c"},
+				"quantity":       "many",
+				"code":           "ab",
+			},
+			expected: Errors{
+				"user[email]":    {"has invalid format"},
+				"items[0][name]": {"is required"},
+				"tags":           {"must be at most 2 items"},
+				"quantity":       {`invalid integer "many"`},
+				"code":           {"must be at least 3 characters", "must be upper case"},
+			},
+		},
+		{
+			name: "bounds of numbers",
+			input: Params{
+				"user[email]": "john@example.com",
+				"quantity":    "11",
+			},
+			expected: Errors{
+				"quantity": {"must be at most 10"},
+			},
+		},
+		{
+			name: "bounds apply to zero values",
+			input: Params{
+				"user[email]": "john@example.com",
+				"quantity":    "0",
+			},
+			expected: Errors{
+				"quantity": {"must be at least 1"},
+			},
+		},
+		{
+			name: "bounds apply to zero numbers",
+			input: Params{
+				"user[email]": "john@example.com",
+				"quantity":    0,
+			},
+			expected: Errors{
+				"quantity": {"must be at least 1"},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var o decodeOrder
+
+			err := Decode(tc.input, &o, WithValidator("upper", upper))
+
+			var errs Errors
+			assert.ErrorAs(t, err, &errs)
+			assert.Equal(t, tc.expected, errs)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	o := decodeOrder{Quantity: 1}
+
+	err := Validate(&o)
+	assert.EqualError(t, err, "params: code: unknown validator upper; user[email]: is required")
+
+	o.User.Email = "john@example.com"
+	o.Code = "ABC"
+	assert.NoError(t, Validate(o, WithValidator("upper", upper)))
+}
@@ -0,0 +1,42 @@
+package params
+
+import (
+	"sort"
+	"strings"
+)
+
+// Errors maps the path of invalid fields, in the form encoding like
+// address[city], to their error messages.
+type Errors map[string][]string
+
+// Add adds an error message for the field.
+func (e Errors) Add(field, msg string) {
+	e[field] = append(e[field], msg)
+}
+
+// Get returns the error messages of the field.
+func (e Errors) Get(field string) []string {
+	return e[field]
+}
+
+func (e Errors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var b strings.Builder
+	b.WriteString("params: ")
+
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(field)
+		b.WriteString(": ")
+		b.WriteString(strings.Join(e[field], ", "))
+	}
+
+	return b.String()
+}
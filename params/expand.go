@@ -0,0 +1,140 @@
+package params
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Expand nests the keys of p in the form encoding, so user[name] becomes
+// the name key of the user map and tags[] appends to the tags list. Keys
+// with [] take a list of values, which are set in turns: the first value
+// of every key, then the second and so on, so items[][name] and
+// items[][qty] build the same maps as in the query string. Keys without
+// brackets are kept as is.
+func Expand(p Params) Params {
+	keys := make([]string, 0, len(p))
+	nested := false
+
+	for k := range p {
+		keys = append(keys, k)
+		nested = nested || strings.Contains(k, "[")
+	}
+
+	if !nested {
+		return p
+	}
+
+	// sorted so that repeated expansions build the same lists.
+	sort.Strings(keys)
+
+	paths := make([][]string, len(keys))
+	values := make([][]any, len(keys))
+	turns := 0
+
+	for i, k := range keys {
+		paths[i] = splitKey(k)
+		values[i] = []any{p[k]}
+
+		if list, ok := p[k].([]any); ok && slices.Contains(paths[i][1:], "") {
+			values[i] = list
+		}
+
+		turns = max(turns, len(values[i]))
+	}
+
+	out := map[string]any{}
+	for turn := range turns {
+		for i, path := range paths {
+			if turn < len(values[i]) {
+				setPath(out, path, values[i][turn])
+			}
+		}
+	}
+
+	return out
+}
+
+// splitKey splits user[address][city] into user, address and city.
+func splitKey(key string) []string {
+	i := strings.IndexByte(key, '[')
+	if i <= 0 || !strings.HasSuffix(key, "]") {
+		return []string{key}
+	}
+
+	path := []string{key[:i]}
+	rest := key[i:]
+
+	for rest != "" {
+		if rest[0] != '[' {
+			return []string{key}
+		}
+
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return []string{key}
+		}
+
+		path = append(path, rest[1:end])
+		rest = rest[end+1:]
+	}
+
+	return path
+}
+
+// setPath sets value at path in m like Plug does: an empty key appends to
+// a list, and in items[][name] a new map starts once the last one has the
+// key.
+func setPath(m map[string]any, path []string, value any) {
+	key := path[0]
+
+	switch {
+	case len(path) == 1:
+		m[key] = value
+	case path[1] == "" && len(path) == 2:
+		list, _ := m[key].([]any)
+		m[key] = append(list, value)
+	case path[1] == "":
+		list, _ := m[key].([]any)
+
+		var last map[string]any
+		if len(list) > 0 {
+			last, _ = list[len(list)-1].(map[string]any)
+		}
+
+		if last == nil || hasPath(last, path[2:]) {
+			last = map[string]any{}
+			list = append(list, last)
+		}
+
+		m[key] = list
+		setPath(last, path[2:], value)
+	default:
+		child, ok := m[key].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			m[key] = child
+		}
+		setPath(child, path[1:], value)
+	}
+}
+
+func hasPath(m map[string]any, path []string) bool {
+	v, ok := m[path[0]]
+	if !ok {
+		return false
+	}
+
+	if len(path) == 1 {
+		return true
+	}
+
+	// lists keep accumulating in the same map.
+	if path[1] == "" {
+		return false
+	}
+
+	child, ok := v.(map[string]any)
+
+	return ok && hasPath(child, path[1:])
+}
@@ -0,0 +1,81 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	tt := []struct {
+		name     string
+		input    Params
+		expected Params
+	}{
+		{
+			name:     "flat keys",
+			input:    Params{"a": "1"},
+			expected: Params{"a": "1"},
+		},
+		{
+			name: "nested keys",
+			input: Params{
+				"user[name]":            "john",
+				"user[address][city]":   "Paris",
+				"user[address][street]": "Main",
+			},
+			expected: Params{
+				"user": map[string]any{
+					"name": "john",
+					"address": map[string]any{
+						"city":   "Paris",
+						"street": "Main",
+					},
+				},
+			},
+		},
+		{
+			name: "lists",
+			input: Params{
+				"tags[]":    []any{"a", "b"},
+				"user[]":    "c",
+				"ids[0]":    "1",
+				"ids[1]":    "2",
+				"broken[a":  "x",
+				"[odd]":     "y",
+				"trailing]": "z",
+			},
+			expected: Params{
+				"tags":      []any{"a", "b"},
+				"user":      []any{"c"},
+				"ids":       map[string]any{"0": "1", "1": "2"},
+				"broken[a":  "x",
+				"[odd]":     "y",
+				"trailing]": "z",
+			},
+		},
+		{
+			name: "lists of maps",
+			input: Params{
+				"items[][name]": []any{"a", "b"},
+				"items[][qty]":  []any{"1", "2"},
+				"one[][name]":   "c",
+			},
+			expected: Params{
+				"items": []any{
+					map[string]any{"name": "a", "qty": "1"},
+					map[string]any{"name": "b", "qty": "2"},
+				},
+				"one": []any{
+					map[string]any{"name": "c"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Expand(tc.input))
+		})
+	}
+}
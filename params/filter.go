@@ -0,0 +1,67 @@
+package params
+
+import "strings"
+
+// Filtered replaces the values of filtered keys.
+const Filtered = "[FILTERED]"
+
+// Filter returns a copy of p where the values of the keys containing one
+// of keys, ignoring case, are replaced by Filtered, e.g. to log params
+// without passwords. Nested maps and lists are filtered too.
+func Filter(p Params, keys ...string) Params {
+	return filterMap(p, keys)
+}
+
+func filterMap(m map[string]any, keys []string) map[string]any {
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		if filtered(k, keys) {
+			result[k] = Filtered
+			continue
+		}
+
+		result[k] = filterValue(v, keys)
+	}
+
+	return result
+}
+
+func filterValue(v any, keys []string) any {
+	switch v := v.(type) {
+	case Params:
+		return filterMap(v, keys)
+	case map[string]any:
+		return filterMap(v, keys)
+	case []Params:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = filterMap(item, keys)
+		}
+		return result
+	case []map[string]any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = filterMap(item, keys)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = filterValue(item, keys)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+func filtered(key string, keys []string) bool {
+	key = strings.ToLower(key)
+	for _, k := range keys {
+		if strings.Contains(key, strings.ToLower(k)) {
+			return true
+		}
+	}
+
+	return false
+}
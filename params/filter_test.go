@@ -0,0 +1,72 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	tt := []struct {
+		name     string
+		params   Params
+		keys     []string
+		expected Params
+	}{
+		{
+			name:     "flat keys",
+			params:   Params{"name": "john", "password": "secret"},
+			keys:     []string{"password"},
+			expected: Params{"name": "john", "password": Filtered},
+		},
+		{
+			name:     "keys containing a filtered key ignoring case",
+			params:   Params{"_csrf_token": "abc", "Password_Confirmation": "secret", "id": 1},
+			keys:     []string{"password", "token"},
+			expected: Params{"_csrf_token": Filtered, "Password_Confirmation": Filtered, "id": 1},
+		},
+		{
+			name: "nested maps and lists",
+			params: Params{
+				"user": map[string]any{
+					"email":    "john@example.com",
+					"password": "secret",
+				},
+				"cards": []any{
+					map[string]any{"number": "4242", "secret": "123"},
+					"plain",
+				},
+			},
+			keys: []string{"password", "secret"},
+			expected: Params{
+				"user": map[string]any{
+					"email":    "john@example.com",
+					"password": Filtered,
+				},
+				"cards": []any{
+					map[string]any{"number": "4242", "secret": Filtered},
+					"plain",
+				},
+			},
+		},
+		{
+			name:     "no keys",
+			params:   Params{"password": "secret"},
+			expected: Params{"password": "secret"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Filter(tc.params, tc.keys...))
+		})
+	}
+}
+
+func TestFilterKeepsParams(t *testing.T) {
+	p := Params{"user": map[string]any{"password": "secret"}}
+
+	Filter(p, "password")
+
+	assert.Equal(t, "secret", p.Map("user").String("password"))
+}
@@ -0,0 +1,97 @@
+package params
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ParseQuery parses a query string like Plug does. Keys nest with
+// brackets: user[name]=a sets the name key of the user map, tags[]=a
+// appends to the tags list and items[][name]=a appends a map to the items
+// list. A repeated key without brackets keeps its last value. Pairs that
+// fail to unescape are skipped and the first error is returned.
+func ParseQuery(query string) (Params, error) {
+	p := map[string]any{}
+
+	var firstErr error
+
+	for query != "" {
+		var pair string
+		pair, query, _ = strings.Cut(query, "&")
+		if pair == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(pair, "=")
+
+		key, err := url.QueryUnescape(key)
+		if err == nil {
+			value, err = url.QueryUnescape(value)
+		}
+
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		setPath(p, splitKey(key), value)
+	}
+
+	return p, firstErr
+}
+
+// EncodeQuery encodes p into a query string ParseQuery reads back. Keys
+// are sorted, maps nest with brackets and lists repeat their key with [].
+func EncodeQuery(p Params) string {
+	var b strings.Builder
+
+	encodeQuery(&b, "", map[string]any(p))
+
+	return b.String()
+}
+
+func encodeQuery(b *strings.Builder, prefix string, value any) {
+	switch v := value.(type) {
+	case Params:
+		encodeQuery(b, prefix, map[string]any(v))
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			key := url.QueryEscape(k)
+			if prefix != "" {
+				key = prefix + "[" + key + "]"
+			}
+			encodeQuery(b, key, v[k])
+		}
+	case []any:
+		for _, item := range v {
+			encodeQuery(b, prefix+"[]", item)
+		}
+	case []string:
+		for _, item := range v {
+			encodeQuery(b, prefix+"[]", item)
+		}
+	case []int:
+		for _, item := range v {
+			encodeQuery(b, prefix+"[]", item)
+		}
+	case nil:
+		encodeQuery(b, prefix, "")
+	default:
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(prefix)
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(fmt.Sprint(v)))
+	}
+}
@@ -0,0 +1,104 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	tt := []struct {
+		name     string
+		query    string
+		expected Params
+		err      bool
+	}{
+		{
+			name:     "flat keys keep the last value",
+			query:    "a=1&b=x+y&a=2",
+			expected: Params{"a": "2", "b": "x y"},
+		},
+		{
+			name:  "lists",
+			query: "tags[]=a&tags[]=b&tags%5B%5D=c",
+			expected: Params{
+				"tags": []any{"a", "b", "c"},
+			},
+		},
+		{
+			name:  "nested maps",
+			query: "user[name]=john&user[address][city]=Paris&page=2",
+			expected: Params{
+				"user": map[string]any{
+					"name": "john",
+					"address": map[string]any{
+						"city": "Paris",
+					},
+				},
+				"page": "2",
+			},
+		},
+		{
+			name:  "lists of maps",
+			query: "items[][name]=a&items[][qty]=1&items[][name]=b&items[][tags][]=x&items[][tags][]=y",
+			expected: Params{
+				"items": []any{
+					map[string]any{"name": "a", "qty": "1"},
+					map[string]any{"name": "b", "tags": []any{"x", "y"}},
+				},
+			},
+		},
+		{
+			name:     "empty values and pairs",
+			query:    "a=&&b",
+			expected: Params{"a": "", "b": ""},
+		},
+		{
+			name:     "invalid escapes are skipped",
+			query:    "a=%zz&b=1",
+			expected: Params{"b": "1"},
+			err:      true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := ParseQuery(tc.query)
+			assert.Equal(t, tc.err, err != nil)
+			assert.Equal(t, tc.expected, p)
+		})
+	}
+}
+
+func TestEncodeQuery(t *testing.T) {
+	p := Params{
+		"page": 2,
+		"q":    "a b&c",
+		"tags": []string{"x", "y"},
+		"filter": map[string]any{
+			"active": true,
+			"range":  map[string]any{"min": 1.5},
+		},
+		"items": []any{
+			map[string]any{"name": "a"},
+		},
+	}
+
+	query := EncodeQuery(p)
+	assert.Equal(t, "filter[active]=true&filter[range][min]=1.5&items[][name]=a&page=2&q=a+b%26c&tags[]=x&tags[]=y", query)
+
+	parsed, err := ParseQuery(query)
+	assert.NoError(t, err)
+	assert.Equal(t, Params{
+		"page": "2",
+		"q":    "a b&c",
+		"tags": []any{"x", "y"},
+		"filter": map[string]any{
+			"active": "true",
+			"range":  map[string]any{"min": "1.5"},
+		},
+		"items": []any{
+			map[string]any{"name": "a"},
+		},
+	}, parsed)
+}
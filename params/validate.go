@@ -0,0 +1,119 @@
+package params
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var patterns sync.Map
+
+// validate checks v against the validate and pattern tags of field.
+// Bounds apply whenever the field was present, even to zero values;
+// missing fields are left to required.
+func (d *decoder) validate(path string, field reflect.StructField, v reflect.Value, present bool) {
+	rules := field.Tag.Get("validate")
+	pattern := field.Tag.Get("pattern")
+
+	if rules == "" && pattern == "" {
+		return
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		var err error
+
+		switch name {
+		case "":
+			continue
+		case "required":
+			if v.IsZero() {
+				err = fmt.Errorf("is required")
+			}
+		case "min", "max":
+			if present {
+				err = checkBound(name, arg, v)
+			}
+		default:
+			f, ok := d.validators[name]
+			if !ok {
+				err = fmt.Errorf("unknown validator %s", name)
+			} else if !v.IsZero() {
+				err = f(v.Interface())
+			}
+		}
+
+		if err != nil {
+			d.errs.Add(path, err.Error())
+		}
+	}
+
+	if pattern != "" {
+		s := reflect.Indirect(v)
+		if s.Kind() == reflect.String && s.String() != "" {
+			re, err := compilePattern(pattern)
+			if err != nil {
+				d.errs.Add(path, err.Error())
+			} else if !re.MatchString(s.String()) {
+				d.errs.Add(path, "has invalid format")
+			}
+		}
+	}
+}
+
+func checkBound(name, arg string, v reflect.Value) error {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", name, arg)
+	}
+
+	v = reflect.Indirect(v)
+
+	var n float64
+	var unit string
+
+	switch v.Kind() {
+	case reflect.String:
+		n, unit = float64(utf8.RuneCountInString(v.String())), " characters"
+	case reflect.Slice, reflect.Map:
+		n, unit = float64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return nil
+	}
+
+	if name == "min" && n < bound {
+		return fmt.Errorf("must be at least %s%s", arg, unit)
+	}
+
+	if name == "max" && n > bound {
+		return fmt.Errorf("must be at most %s%s", arg, unit)
+	}
+
+	return nil
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q", pattern)
+	}
+
+	patterns.Store(pattern, re)
+
+	return re, nil
+}
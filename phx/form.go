@@ -0,0 +1,70 @@
+package phx
+
+import (
+	"github.com/go-live-view/go-live-view/dynamic"
+	"github.com/go-live-view/go-live-view/form"
+	"github.com/go-live-view/go-live-view/html"
+	"github.com/go-live-view/go-live-view/rend"
+)
+
+// Form creates a form element for f. Bind its events with phx-change and
+// phx-submit attributes among the children.
+func Form(f *form.Form, children ...rend.Node) rend.Node {
+	return html.Form(append([]rend.Node{
+		html.Attrs(
+			dynamic.If(f.ID() != "", html.IdAttr(f.ID())),
+		),
+	}, children...)...)
+}
+
+// Input creates an input for the field, named so that params.Decode reads
+// its value back into the form's struct. attrs set the type and others.
+func Input(field form.Field, attrs ...rend.Node) rend.Node {
+	return html.Input(
+		html.Attrs(
+			dynamic.Group(
+				html.IdAttr(field.ID()),
+				html.NameAttr(field.Name()),
+				html.ValueAttr(field.Value()),
+			),
+			html.Attrs(attrs...),
+		),
+	)
+}
+
+// Checkbox creates a checkbox for a boolean field. A hidden input sends
+// false while it is unchecked.
+func Checkbox(field form.Field, attrs ...rend.Node) rend.Node {
+	return html.Fragment(
+		html.Input(
+			html.Attrs(
+				html.TypeAttr("hidden"),
+				dynamic.Group(html.NameAttr(field.Name())),
+				html.ValueAttr("false"),
+			),
+		),
+		html.Input(
+			html.Attrs(
+				html.TypeAttr("checkbox"),
+				dynamic.Group(
+					html.IdAttr(field.ID()),
+					html.NameAttr(field.Name()),
+				),
+				html.ValueAttr("true"),
+				dynamic.If(field.Value() == "true", html.CheckedAttr()),
+				html.Attrs(attrs...),
+			),
+		),
+	)
+}
+
+// ErrorTag renders the errors of the field, which are only set once the
+// user interacted with its input. attrs are set on each message.
+func ErrorTag(field form.Field, attrs ...rend.Node) rend.Node {
+	return dynamic.Range(field.Errors(), func(msg string) rend.Node {
+		return html.P(
+			html.Attrs(attrs...),
+			dynamic.Text(msg),
+		)
+	})
+}
@@ -1,6 +1,8 @@
 package phx
 
 import (
+	"strings"
+
 	"github.com/go-live-view/go-live-view/html"
 	"github.com/go-live-view/go-live-view/rend"
 )
@@ -15,7 +17,9 @@ type title struct {
 	children []rend.Node
 }
 
-// Title creates a title with automatic prefix/suffix on page_title updates
+// Title creates a title with automatic prefix/suffix on page_title updates.
+// When the page has a title it is rendered with the prefix and suffix in
+// place of the slot.
 func Title(options ...TitleOption) rend.Node {
 	t := &title{}
 
@@ -23,14 +27,25 @@ func Title(options ...TitleOption) rend.Node {
 		option(t)
 	}
 
+	return t
+}
+
+func (t *title) Render(diff bool, root *rend.Root, r *rend.Rend, b *strings.Builder) error {
+	children := t.children
+	if root.Title != "" {
+		children = []rend.Node{
+			html.Text(t.prefix + root.Title + t.suffix),
+		}
+	}
+
 	return html.Title(
 		html.DataAttr("prefix", t.prefix),
 		html.DataAttr("default", t.def),
 		html.DataAttr("suffix", t.suffix),
 		html.Attr("phx-no-format"),
 		html.Attrs(t.attrs...),
-		html.Fragment(t.children...),
-	)
+		html.Fragment(children...),
+	).Render(diff, root, r, b)
 }
 
 func TitlePrefix(prefix string) TitleOption {
@@ -7,19 +7,32 @@ import (
 func (oldRoot *Root) Diff(newRoot *Root) *Root {
 	// if the root fingerprint changed, force a full render
 	if oldRoot.Rend.Fingerprint != newRoot.Rend.Fingerprint {
-		return newRoot
+		root := *newRoot
+		root.Title = compareTitle(oldRoot.Title, newRoot.Title)
+		return &root
 	}
 
 	root := compareComponents(oldRoot, newRoot)
 	root.Rend = compareRend(oldRoot.Rend, newRoot.Rend)
+	root.Title = compareTitle(oldRoot.Title, newRoot.Title)
 
-	if root.Components == nil && root.Rend == nil {
+	if root.Components == nil && root.Rend == nil && root.Title == "" {
 		return nil
 	}
 
 	return root
 }
 
+// compareTitle returns the new title if it changed. The client keeps the
+// current title when none is sent, so a title can't be cleared.
+func compareTitle(oldTitle, newTitle string) string {
+	if oldTitle == newTitle {
+		return ""
+	}
+
+	return newTitle
+}
+
 func compareComponents(oldRoot, newRoot *Root) *Root {
 	root := &Root{}
 
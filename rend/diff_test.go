@@ -407,6 +407,59 @@ func TestDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "title changed",
+			a: &Root{
+				Title: "a",
+				Rend: &Rend{
+					Static:      []string{"a", "b"},
+					Fingerprint: "123",
+				},
+			},
+			b: &Root{
+				Title: "b",
+				Rend: &Rend{
+					Static:      []string{"a", "b"},
+					Fingerprint: "123",
+				},
+			},
+		},
+		{
+			name: "title unchanged",
+			a: &Root{
+				Title: "a",
+				Rend: &Rend{
+					Dynamic: map[string]interface{}{
+						"0": "a",
+					},
+				},
+			},
+			b: &Root{
+				Title: "a",
+				Rend: &Rend{
+					Dynamic: map[string]interface{}{
+						"0": "b",
+					},
+				},
+			},
+		},
+		{
+			name: "title unchanged with statics changed",
+			a: &Root{
+				Title: "a",
+				Rend: &Rend{
+					Static:      []string{"a", "b", "c"},
+					Fingerprint: "123",
+				},
+			},
+			b: &Root{
+				Title: "a",
+				Rend: &Rend{
+					Static:      []string{"a", "b", "c", "d"},
+					Fingerprint: "1234",
+				},
+			},
+		},
 	}
 
 	for _, tc := range tt {
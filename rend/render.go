@@ -12,8 +12,13 @@ type Node interface {
 }
 
 func RenderString(n Node) string {
+	return RenderRootString(NewRoot(), n)
+}
+
+// RenderRootString renders n to a string using root, which lets nodes
+// read root wide values such as the page title.
+func RenderRootString(root *Root, n Node) string {
 	b := &strings.Builder{}
-	root := NewRoot()
 
 	render(false, root, root.Rend, b, n)
 
@@ -21,8 +26,11 @@ func RenderString(n Node) string {
 }
 
 func RenderTree(n Node) *Root {
-	root := NewRoot()
+	return RenderRootTree(NewRoot(), n)
+}
 
+// RenderRootTree renders n into root.
+func RenderRootTree(root *Root, n Node) *Root {
 	b := &strings.Builder{}
 
 	render(true, root, root.Rend, b, n)
@@ -11,6 +11,7 @@ import (
 type Root struct {
 	refCID    *ref.Ref
 	streamRef *ref.Ref
+	values    map[any]any
 
 	Components map[int64]*Rend `json:"c,omitempty"`
 	Title      string          `json:"t,omitempty"`
@@ -45,9 +46,36 @@ func (r *Root) NextStreamID() int64 {
 	return r.streamRef.NextRef()
 }
 
+// NextCID returns an unused component ID.
+func (r *Root) NextCID() int64 {
+	return r.refCID.NextRef()
+}
+
+// SkipCIDs makes NextCID return IDs greater than cid, leaving the lower
+// IDs to components with a stable ID.
+func (r *Root) SkipCIDs(cid int64) {
+	r.refCID = ref.New(cid)
+}
+
+// SetValue stores a value for nodes to read during the render.
+func (r *Root) SetValue(key, value any) {
+	if r.values == nil {
+		r.values = map[any]any{}
+	}
+	r.values[key] = value
+}
+
+// Value returns the value stored with SetValue for key.
+func (r *Root) Value(key any) any {
+	return r.values[key]
+}
+
 func (rend *Rend) AddComponent(r *Root, c *Rend) {
-	id := r.refCID.NextRef()
+	rend.AddComponentCID(r, r.NextCID(), c)
+}
 
+// AddComponentCID adds a component with a given ID.
+func (rend *Rend) AddComponentCID(r *Root, id int64, c *Rend) {
 	rend.AddDynamic(id)
 
 	if r.Components == nil {
@@ -1,9 +1,11 @@
 package router
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/go-live-view/go-live-view/internal/tree"
@@ -20,11 +22,15 @@ type routeOption func(*route)
 
 type router struct {
 	root     *tree.Node[*route]
+	routes   []*route
+	names    map[string]*route
+	sessions map[string]*liveSession
 	mounted  map[*route]bool
 	layout   func(...rend.Node) rend.Node
 	notFound *route
 }
 type route struct {
+	name    string
 	path    string
 	view    lv.View
 	params  params.Params
@@ -35,6 +41,11 @@ type route struct {
 
 	httpMounts []func(http.ResponseWriter, *http.Request, params.Params) error
 	mounts     []func(lv.Socket, params.Params) error
+	middleware []lv.Middleware
+	live       *liveSession
+	rootLayout func(...rend.Node) rend.Node
+	rootName   string
+	layouts    []func(...rend.Node) rend.Node
 }
 
 type routeGroup struct {
@@ -50,9 +61,11 @@ func NewRouter(
 ) *router {
 
 	r := &router{
-		root:    tree.New[*route](),
-		mounted: make(map[*route]bool),
-		layout:  layout,
+		root:     tree.New[*route](),
+		names:    make(map[string]*route),
+		sessions: make(map[string]*liveSession),
+		mounted:  make(map[*route]bool),
+		layout:   layout,
 		notFound: &route{
 			view: &notFound{},
 		},
@@ -86,6 +99,14 @@ func WithParams(params params.Params) routeOption {
 	}
 }
 
+// WithName names the route so that URL builds its path. The routes of a
+// group don't inherit its name.
+func WithName(name string) routeOption {
+	return func(r *route) {
+		r.name = name
+	}
+}
+
 func WithSession(session string) routeOption {
 	return func(r *route) {
 		r.session = session
@@ -104,6 +125,34 @@ func WithMount(middleware func(lv.Socket, params.Params) error) routeOption {
 	}
 }
 
+// WithRootLayout renders the route in layout instead of the layout of the
+// router or of its live session. The root layout is only rendered over
+// HTTP, navigating to a route with another one reloads the page. Routes
+// given the same name share the layout.
+func WithRootLayout(name string, layout func(...rend.Node) rend.Node) routeOption {
+	return func(r *route) {
+		r.rootName = name
+		r.rootLayout = layout
+	}
+}
+
+// WithLayout wraps the views of the route in layout, as part of the live
+// tree so that it updates and persists across live navigation. The layouts
+// of a group wrap the ones of its routes.
+func WithLayout(layout func(...rend.Node) rend.Node) routeOption {
+	return func(r *route) {
+		r.layouts = append(r.layouts, layout)
+	}
+}
+
+// WithMiddleware wraps the mount of the route with middleware, first one
+// outermost. The routes of a group run its middleware before their own.
+func WithMiddleware(middleware ...lv.Middleware) routeOption {
+	return func(r *route) {
+		r.middleware = append(r.middleware, middleware...)
+	}
+}
+
 func (r *router) GetLayout() func(...rend.Node) rend.Node {
 	return r.layout
 }
@@ -119,7 +168,7 @@ func (r *router) Group(path string, view lv.View, opts ...routeOption) *routeGro
 		opt(route)
 	}
 
-	r.root.AddRoute(path, route)
+	r.addRoute(path, route)
 
 	return &routeGroup{
 		router:  r,
@@ -140,12 +189,98 @@ func (r *router) Handle(path string, view lv.View, opts ...routeOption) *route {
 		opt(route)
 	}
 
-	r.root.AddRoute(path, route)
+	r.addRoute(path, route)
 	return route
 }
 
+// addRoute panics when path is invalid or conflicts with another route,
+// like http.ServeMux does for patterns.
+func (r *router) addRoute(path string, route *route) {
+	err := r.root.AddRoute(path, route)
+	if err != nil {
+		panic("router: " + err.Error())
+	}
+
+	if route.name != "" {
+		if named, ok := r.names[route.name]; ok {
+			panic(fmt.Sprintf("router: route %s is named %s like %s", path, route.name, named.path))
+		}
+		r.names[route.name] = route
+	}
+
+	r.routes = append(r.routes, route)
+}
+
+// RouteInfo describes a route added to the router.
+type RouteInfo struct {
+	Name string
+	Path string
+}
+
+// Routes returns the routes of the router in the order they were added.
+func (r *router) Routes() []RouteInfo {
+	routes := make([]RouteInfo, len(r.routes))
+	for i, route := range r.routes {
+		routes[i] = RouteInfo{
+			Name: route.name,
+			Path: route.path,
+		}
+	}
+
+	return routes
+}
+
+// URL returns the URL of the route named name. p fills the params of its
+// path, the others are encoded in the query string. It fails if the route
+// is unknown or a param of its path is missing or invalid.
+func (r *router) URL(name string, p params.Params) (string, error) {
+	route, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("router: unknown route %s", name)
+	}
+
+	values := make(map[string]string, len(p))
+	for key, value := range p {
+		// ids are often ints, uuids or other Stringers.
+		if value != nil {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+
+	path, used, err := tree.Fill(route.path, values)
+	if err != nil {
+		return "", fmt.Errorf("router: route %s: %w", name, err)
+	}
+
+	query := params.Params{}
+	for key, value := range p {
+		query[key] = value
+	}
+
+	for _, key := range used {
+		delete(query, key)
+	}
+
+	if q := params.EncodeQuery(query); q != "" {
+		path += "?" + q
+	}
+
+	return path, nil
+}
+
+// MustURL is like URL but panics on errors, for routes known to exist.
+// Views use liveview.Href in Render.
+func (r *router) MustURL(name string, p params.Params) string {
+	url, err := r.URL(name, p)
+	if err != nil {
+		panic(err)
+	}
+
+	return url
+}
+
 func (r *router) GetRoute(path string) (lv.Route, error) {
-	node, params, err := r.findNode(path)
+	node, found, err := r.findNode(path)
 	if err != nil {
 		return nil, err
 	}
@@ -156,19 +291,18 @@ func (r *router) GetRoute(path string) (lv.Route, error) {
 		return r.notFound, lv.NotFoundError
 	}
 
-	if route.params == nil {
-		route.params = make(map[string]any)
-	}
-
-	for key, value := range params {
-		route.params[key] = value
-	}
+	// a copy per lookup, so that the params of a path don't stay for the
+	// next one, e.g. a filter removed from the query.
+	matched := *route
+	matched.params = params.Merge(route.params, found)
 
-	return route, nil
+	return &matched, nil
 }
 
 func (r *router) Routable(from lv.Route, to lv.Route) bool {
-	return r.sameSession(from, to)
+	// routes of a session without their own root layout share the one of
+	// the session or of the router.
+	return r.sameSession(from, to) && from.(*route).rootName == to.(*route).rootName
 }
 
 func (r *router) sameSession(from lv.Route, to lv.Route) bool {
@@ -181,16 +315,18 @@ func (r *router) findNode(path string) (*tree.Node[*route], map[string]any, erro
 		return nil, nil, err
 	}
 
-	node, params, err := r.root.FindNode(u.Path)
+	node, routeParams, err := r.root.FindNode(u.Path)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	for key, value := range u.Query() {
-		params[key] = value[0]
+	// bad pairs are skipped, the rest of the query still applies.
+	query, _ := params.ParseQuery(u.RawQuery)
+	for key, value := range query {
+		routeParams[key] = value
 	}
 
-	return node, params, nil
+	return node, routeParams, nil
 }
 
 func (r *route) GetView() lv.View {
@@ -209,6 +345,24 @@ func (r *route) GetMounts() []func(lv.Socket, params.Params) error {
 	return r.mounts
 }
 
+func (r *route) GetMiddleware() []lv.Middleware {
+	return slices.Concat(r.live.middleware(), r.middleware)
+}
+
+// GetLayout returns the root layout of the route or of its live session,
+// nil to use the layout of the router.
+func (r *route) GetLayout() func(...rend.Node) rend.Node {
+	if r.rootLayout != nil {
+		return r.rootLayout
+	}
+
+	if r.live == nil {
+		return nil
+	}
+
+	return r.live.layout
+}
+
 func (rg *routeGroup) Group(path string, view lv.View, opts ...routeOption) *routeGroup {
 	fullPath := rg.combinePaths(rg.path, path)
 
@@ -219,17 +373,15 @@ func (rg *routeGroup) Group(path string, view lv.View, opts ...routeOption) *rou
 		parent: rg.parent,
 	}
 
-	for _, opt := range append(rg.options, opts...) {
-		opt(route)
-	}
+	rg.apply(route, opts)
 
 	// Add the group's route to the router
-	rg.router.root.AddRoute(fullPath, route)
+	rg.router.addRoute(fullPath, route)
 
 	return &routeGroup{
 		router:  rg.router,
 		path:    fullPath,
-		options: append(rg.options, opts...),
+		options: slices.Concat(rg.options, opts),
 		parent:  route,
 	}
 }
@@ -244,12 +396,24 @@ func (rg *routeGroup) Handle(path string, view lv.View, opts ...routeOption) *ro
 		parent: rg.parent,
 	}
 
-	for _, opt := range append(rg.options, opts...) {
+	rg.apply(route, opts)
+
+	rg.router.addRoute(fullPath, route)
+	return route
+}
+
+// apply applies the options of the group, then opts, to route. Names are
+// not inherited from the group.
+func (rg *routeGroup) apply(route *route, opts []routeOption) {
+	for _, opt := range rg.options {
 		opt(route)
 	}
 
-	rg.router.root.AddRoute(fullPath, route)
-	return route
+	route.name = ""
+
+	for _, opt := range opts {
+		opt(route)
+	}
 }
 
 func (rg *routeGroup) combinePaths(base, new string) string {
@@ -116,6 +116,41 @@ func TestGetRoute(t *testing.T) {
 				"b": "2",
 			},
 		},
+		{
+			name: "nested and list query params",
+			path: "/test?user[name]=john&tags[]=a&tags[]=b&page=1&page=2",
+			routes: []routes{
+				{path: "/test", lv: &testLive{
+					name: "test",
+				}},
+			},
+			expected: "<div>test</div>",
+			expectedParams: params.Params{
+				"user": map[string]any{"name": "john"},
+				"tags": []any{"a", "b"},
+				"page": "2",
+			},
+		},
+		{
+			name: "nested routes with typed params and catch-all",
+			path: "/docs/2/guides/routing.md",
+			routes: []routes{
+				{path: "/docs/:version<int>", lv: &testLive{
+					name: "docs",
+				},
+					children: []routes{
+						{path: "/*path", lv: &testLive{
+							name: "page",
+						}},
+					},
+				},
+			},
+			expected: "<div>docs<div>page</div></div>",
+			expectedParams: params.Params{
+				"version": "2",
+				"path":    "guides/routing.md",
+			},
+		},
 		{
 			name: "simple routes with extra params",
 			path: "/",
@@ -267,6 +302,52 @@ func TestGetRoute(t *testing.T) {
 	}
 }
 
+func TestGetRouteParamsPerLookup(t *testing.T) {
+	rt := NewRouter(testLayout)
+	rt.Handle("/search", &testLive{name: "search"}, WithParams(params.Params{"scope": "all"}))
+	rt.Handle("/posts/:page<int>?", &testLive{name: "posts"})
+
+	tt := []struct {
+		name   string
+		from   string
+		to     string
+		expect params.Params
+	}{
+		{
+			name:   "query keys removed",
+			from:   "/search?tags[]=a&q=x",
+			to:     "/search?q=y",
+			expect: params.Params{"scope": "all", "q": "y"},
+		},
+		{
+			name:   "optional segment removed",
+			from:   "/posts/2",
+			to:     "/posts",
+			expect: params.Params{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := rt.GetRoute(tc.from)
+			assert.NoError(t, err)
+
+			route, err := rt.GetRoute(tc.to)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, route.GetParams())
+		})
+	}
+}
+
+func TestHandleConflict(t *testing.T) {
+	rt := NewRouter(testLayout)
+	rt.Handle("/users/:id", &testLive{name: "show"})
+
+	assert.PanicsWithValue(t, "router: route /users/:name: :name conflicts with :id", func() {
+		rt.Handle("/users/:name", &testLive{name: "other"})
+	})
+}
+
 func TestRoutable(t *testing.T) {
 	tt := []struct {
 		name     string
@@ -397,3 +478,491 @@ func createRoutes(rt handler, routes []routes) {
 		}
 	}
 }
+
+type titledLive struct {
+	testLive
+	title string
+}
+
+func (t *titledLive) PageTitle() string {
+	return t.title
+}
+
+func TestPageTitle(t *testing.T) {
+	tt := []struct {
+		name     string
+		path     string
+		routes   []routes
+		expected string
+	}{
+		{
+			name: "no title",
+			path: "/test",
+			routes: []routes{
+				{path: "/test", lv: &testLive{name: "test"}},
+			},
+			expected: "",
+		},
+		{
+			name: "parent title",
+			path: "/test/child",
+			routes: []routes{
+				{path: "/test", lv: &titledLive{title: "parent"}, children: []routes{
+					{path: "/child", lv: &testLive{name: "child"}},
+				}},
+			},
+			expected: "parent",
+		},
+		{
+			name: "child title wins",
+			path: "/test/child",
+			routes: []routes{
+				{path: "/test", lv: &titledLive{title: "parent"}, children: []routes{
+					{path: "/child", lv: &titledLive{title: "child"}},
+				}},
+			},
+			expected: "child",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := NewRouter(
+				testLayout,
+			)
+			createRoutes(rt, tc.routes)
+
+			route, err := rt.GetRoute(tc.path)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expected, lv.TryPageTitle(route.GetView()))
+		})
+	}
+}
+
+// boundLive records the events it binds with lv.On.
+type boundLive struct {
+	testLive
+	events []string
+	calls  *[]string
+}
+
+func (b *boundLive) Events() []lv.EventBinding {
+	bindings := []lv.EventBinding{}
+	for _, event := range b.events {
+		bindings = append(bindings, lv.On(event, func(lv.Socket, params.Params) error {
+			*b.calls = append(*b.calls, b.name+":"+event)
+			return nil
+		}))
+	}
+
+	return bindings
+}
+
+type handlerLive struct {
+	testLive
+	calls *[]string
+}
+
+func (h *handlerLive) Event(_ lv.Socket, event string, _ params.Params) error {
+	*h.calls = append(*h.calls, h.name+":"+event)
+	return nil
+}
+
+func TestEventDispatch(t *testing.T) {
+	tt := []struct {
+		name     string
+		event    string
+		routes   func(calls *[]string) []routes
+		expected []string
+		err      error
+	}{
+		{
+			name:  "child handles",
+			event: "save",
+			routes: func(calls *[]string) []routes {
+				return []routes{
+					{path: "/test", lv: &boundLive{testLive{"parent"}, []string{"save"}, calls}, children: []routes{
+						{path: "/child", lv: &boundLive{testLive{"child"}, []string{"save"}, calls}},
+					}},
+				}
+			},
+			expected: []string{"child:save"},
+		},
+		{
+			name:  "parent handles what the child does not",
+			event: "save",
+			routes: func(calls *[]string) []routes {
+				return []routes{
+					{path: "/test", lv: &boundLive{testLive{"parent"}, []string{"save"}, calls}, children: []routes{
+						{path: "/child", lv: &boundLive{testLive{"child"}, []string{"delete"}, calls}},
+					}},
+				}
+			},
+			expected: []string{"parent:save"},
+		},
+		{
+			name:  "event handlers are called on the way",
+			event: "save",
+			routes: func(calls *[]string) []routes {
+				return []routes{
+					{path: "/test", lv: &boundLive{testLive{"parent"}, []string{"save"}, calls}, children: []routes{
+						{path: "/child", lv: &handlerLive{testLive{"child"}, calls}},
+					}},
+				}
+			},
+			expected: []string{"child:save", "parent:save"},
+		},
+		{
+			name:  "unknown event",
+			event: "delete",
+			routes: func(calls *[]string) []routes {
+				return []routes{
+					{path: "/test", lv: &boundLive{testLive{"parent"}, []string{"save"}, calls}, children: []routes{
+						{path: "/child", lv: &testLive{"child"}},
+					}},
+				}
+			},
+			err: lv.ErrUnknownEvent,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+
+			rt := NewRouter(testLayout)
+			createRoutes(rt, tc.routes(&calls))
+
+			route, err := rt.GetRoute("/test/child")
+			assert.NoError(t, err)
+
+			err = lv.DispatchEvent(route.GetView(), nil, tc.event, params.Params{})
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, calls)
+		})
+	}
+}
+
+func namedRouter() *router {
+	rt := NewRouter(testLayout)
+	rt.Handle("/", &testLive{name: "home"}, WithName("home"))
+	rt.Handle("/files/*path", &testLive{name: "file"}, WithName("file"))
+
+	users := rt.Group("/users", &testLive{name: "users"}, WithName("user.index"))
+	users.Handle("/:id<int>", &testLive{name: "show"}, WithName("user.show"))
+	users.Handle("/:id<int>/edit", &testLive{name: "edit"}, WithName("user.edit"))
+	users.Handle("/new", &testLive{name: "new"})
+
+	rt.Handle("/posts/:page<int>?", &testLive{name: "page"}, WithName("post.page"))
+
+	return rt
+}
+
+type stringer string
+
+func (s stringer) String() string { return string(s) }
+
+func TestURL(t *testing.T) {
+	tt := []struct {
+		name   string
+		route  string
+		params params.Params
+		expect string
+		err    string
+	}{
+		{
+			name:   "static",
+			route:  "home",
+			expect: "/",
+		},
+		{
+			name:   "param",
+			route:  "user.edit",
+			params: params.Params{"id": 42},
+			expect: "/users/42/edit",
+		},
+		{
+			name:   "non string params",
+			route:  "user.edit",
+			params: params.Params{"id": uint32(7), "ref": stringer("abc")},
+			expect: "/users/7/edit?ref=abc",
+		},
+		{
+			name:   "query string",
+			route:  "user.show",
+			params: params.Params{"id": "1", "tab": "posts", "filter": map[string]any{"tags": []string{"a"}}},
+			expect: "/users/1?filter[tags][]=a&tab=posts",
+		},
+		{
+			name:   "catch-all",
+			route:  "file",
+			params: params.Params{"path": "docs/read me.md"},
+			expect: "/files/docs/read%20me.md",
+		},
+		{
+			name:   "optional param",
+			route:  "post.page",
+			expect: "/posts",
+		},
+		{
+			name:  "unknown route",
+			route: "user.delete",
+			err:   "router: unknown route user.delete",
+		},
+		{
+			name:  "missing param",
+			route: "user.edit",
+			err:   "router: route user.edit: missing param id",
+		},
+		{
+			name:   "invalid param",
+			route:  "user.show",
+			params: params.Params{"id": "me"},
+			err:    `router: route user.show: param id: "me" does not match :id<int>`,
+		},
+	}
+
+	rt := namedRouter()
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			url, err := rt.URL(tc.route, tc.params)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, url)
+		})
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	rt := namedRouter()
+
+	assert.Equal(t, []RouteInfo{
+		{Name: "home", Path: "/"},
+		{Name: "file", Path: "/files/*path"},
+		{Name: "user.index", Path: "/users"},
+		{Name: "user.show", Path: "/users/:id<int>"},
+		{Name: "user.edit", Path: "/users/:id<int>/edit"},
+		{Name: "", Path: "/users/new"},
+		{Name: "post.page", Path: "/posts/:page<int>?"},
+	}, rt.Routes())
+
+	// the URL of every named route leads back to it.
+	values := params.Params{"id": "7", "path": "a/b", "page": "3"}
+
+	for _, info := range rt.Routes() {
+		if info.Name == "" {
+			continue
+		}
+
+		url, err := rt.URL(info.Name, values)
+		assert.NoError(t, err)
+
+		found, err := rt.GetRoute(url)
+		assert.NoError(t, err)
+		assert.Equal(t, info.Path, found.(*route).path, info.Name)
+	}
+}
+
+func TestDuplicateName(t *testing.T) {
+	rt := NewRouter(testLayout)
+	rt.Handle("/a", &testLive{name: "a"}, WithName("page"))
+
+	assert.PanicsWithValue(t, "router: route /b is named page like /a", func() {
+		rt.Handle("/b", &testLive{name: "b"}, WithName("page"))
+	})
+}
+
+func TestMiddlewareInherited(t *testing.T) {
+	var calls []string
+
+	trace := func(name string) lv.Middleware {
+		return func(next lv.MountHandler) lv.MountHandler {
+			return func(c *lv.Conn) error {
+				calls = append(calls, name)
+				return next(c)
+			}
+		}
+	}
+
+	rt := NewRouter(testLayout)
+
+	app := rt.Group("/app", &testLive{name: "app"}, WithMiddleware(trace("app")))
+	admin := app.Group("/admin", &testLive{name: "admin"}, WithMiddleware(trace("admin")))
+	app.Group("/public", &testLive{name: "public"}, WithMiddleware(trace("public")))
+	admin.Handle("/users", &testLive{name: "users"}, WithMiddleware(trace("users")))
+
+	route, err := rt.GetRoute("/app/admin/users")
+	assert.NoError(t, err)
+
+	middleware := route.GetMiddleware()
+	assert.Len(t, middleware, 3)
+
+	h := func(*lv.Conn) error { return nil }
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+
+	assert.NoError(t, h(&lv.Conn{}))
+	assert.Equal(t, []string{"app", "admin", "users"}, calls)
+}
+
+func TestLiveSession(t *testing.T) {
+	var calls []string
+
+	trace := func(name string) lv.Middleware {
+		return func(next lv.MountHandler) lv.MountHandler {
+			return func(c *lv.Conn) error {
+				calls = append(calls, name)
+				return next(c)
+			}
+		}
+	}
+
+	adminLayout := func(children ...rend.Node) rend.Node {
+		return html.Main(children...)
+	}
+
+	rt := NewRouter(testLayout)
+	rt.Handle("/login", &testLive{name: "login"})
+
+	admin := rt.LiveSession("admin",
+		OnMount(trace("hook")),
+		RequireAuth(func(session params.Params) bool {
+			calls = append(calls, "auth")
+			return session.String("user") == "admin"
+		}, "/login"),
+		WithSessionLayout(adminLayout),
+	)
+	admin.Handle("/admin", &testLive{name: "admin"})
+	admin.Group("/admin/users", &testLive{name: "users"}, WithMiddleware(trace("users"))).
+		Handle("/:id", &testLive{name: "user"})
+
+	login, err := rt.GetRoute("/login")
+	assert.NoError(t, err)
+	users, err := rt.GetRoute("/admin/users/1")
+	assert.NoError(t, err)
+	dashboard, err := rt.GetRoute("/admin")
+	assert.NoError(t, err)
+
+	assert.True(t, rt.Routable(dashboard, users))
+	assert.False(t, rt.Routable(login, users))
+
+	assert.Nil(t, login.GetLayout())
+	assert.NotNil(t, users.GetLayout())
+
+	mount := func(route lv.Route, session params.Params) error {
+		h := func(*lv.Conn) error {
+			calls = append(calls, "mount")
+			return nil
+		}
+
+		middleware := route.GetMiddleware()
+		for i := len(middleware) - 1; i >= 0; i-- {
+			h = middleware[i](h)
+		}
+
+		return h(&lv.Conn{
+			Params:  params.Merge(route.GetParams(), session),
+			Session: session,
+		})
+	}
+
+	assert.NoError(t, mount(users, params.Params{"user": "admin"}))
+	assert.Equal(t, []string{"auth", "hook", "users", "mount"}, calls)
+
+	calls = nil
+
+	err = mount(dashboard, params.Params{})
+	assert.Equal(t, lv.RedirectTo("/login"), err)
+	assert.Equal(t, []string{"auth"}, calls)
+
+	calls = nil
+
+	// the query string does not stand in for the session.
+	forged, err := rt.GetRoute("/admin?user=admin")
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", forged.GetParams().String("user"))
+
+	err = mount(forged, nil)
+	assert.Equal(t, lv.RedirectTo("/login"), err)
+	assert.Equal(t, []string{"auth"}, calls)
+
+	assert.Panics(t, func() {
+		rt.LiveSession("admin")
+	})
+}
+
+func TestLayouts(t *testing.T) {
+	section := func(name string) func(...rend.Node) rend.Node {
+		return func(children ...rend.Node) rend.Node {
+			return html.Section(append([]rend.Node{html.Text(name)}, children...)...)
+		}
+	}
+
+	marketing := func(children ...rend.Node) rend.Node {
+		return html.Body(children...)
+	}
+
+	shell := func(children ...rend.Node) rend.Node {
+		return html.Main(children...)
+	}
+
+	rt := NewRouter(testLayout)
+	rt.Handle("/", &testLive{name: "home"}, WithRootLayout("marketing", marketing))
+	rt.Handle("/pricing", &testLive{name: "pricing"}, WithRootLayout("marketing", marketing))
+	rt.Handle("/about", &testLive{name: "about"})
+	rt.Handle("/docs", &testLive{name: "docs"}, WithRootLayout("docs", section("docs")))
+	rt.Handle("/blog", &testLive{name: "blog"}, WithRootLayout("blog", section("blog")))
+
+	app := rt.Group("/app", &testLive{name: "app"},
+		WithRootLayout("shell", shell),
+		WithLayout(section("shell")),
+	)
+	app.Handle("/settings", &testLive{name: "settings"}, WithLayout(section("settings")))
+	app.Handle("/profile", &testLive{name: "profile"})
+
+	route := func(path string) lv.Route {
+		route, err := rt.GetRoute(path)
+		assert.NoError(t, err)
+		return route
+	}
+
+	render := func(path string) string {
+		node, err := route(path).GetView().Render(nil)
+		assert.NoError(t, err)
+		return rend.RenderString(node)
+	}
+
+	assert.Equal(t, "<div>home</div>", render("/"))
+	assert.Equal(t,
+		"<section>shell<section>settings<div>app<div>settings</div></div></section></section>",
+		render("/app/settings"),
+	)
+	assert.Equal(t,
+		"<section>shell<div>app<div>profile</div></div></section>",
+		render("/app/profile"),
+	)
+
+	tt := []struct {
+		from     string
+		to       string
+		routable bool
+	}{
+		{from: "/", to: "/pricing", routable: true},
+		{from: "/app/settings", to: "/app/profile", routable: true},
+		{from: "/", to: "/app/settings", routable: false},
+		{from: "/about", to: "/pricing", routable: false},
+		// closures of the same function are different layouts.
+		{from: "/docs", to: "/blog", routable: false},
+	}
+
+	for _, tc := range tt {
+		assert.Equal(t, tc.routable, rt.Routable(route(tc.from), route(tc.to)), tc.from+" -> "+tc.to)
+	}
+}
@@ -0,0 +1,99 @@
+package router
+
+import (
+	"fmt"
+	"slices"
+
+	lv "github.com/go-live-view/go-live-view/liveview"
+	"github.com/go-live-view/go-live-view/params"
+	"github.com/go-live-view/go-live-view/rend"
+)
+
+type sessionOption func(*liveSession)
+
+// liveSession is a named set of routes sharing on_mount hooks, a layout
+// and an auth requirement. Navigating between its routes keeps the
+// connection, while leaving it reloads the page.
+type liveSession struct {
+	name   string
+	auth   []lv.Middleware
+	hooks  []lv.Middleware
+	layout func(...rend.Node) rend.Node
+}
+
+// OnMount adds hooks running on the HTTP render and on every join of the
+// routes of the session, including live navigation between them. They run
+// before the middleware of the routes, first one outermost.
+func OnMount(hooks ...lv.Middleware) sessionOption {
+	return func(s *liveSession) {
+		s.hooks = append(s.hooks, hooks...)
+	}
+}
+
+// RequireAuth redirects to redirect the mounts whose session authorized
+// rejects. It gets the values of the session store, not the params the
+// client controls, and runs before the OnMount hooks.
+func RequireAuth(authorized func(session params.Params) bool, redirect string) sessionOption {
+	return func(s *liveSession) {
+		s.auth = append(s.auth, func(next lv.MountHandler) lv.MountHandler {
+			return func(c *lv.Conn) error {
+				if !authorized(c.Session) {
+					return lv.RedirectTo(redirect)
+				}
+
+				return next(c)
+			}
+		})
+	}
+}
+
+// WithSessionLayout renders the routes of the session in layout instead of
+// the layout of the router.
+func WithSessionLayout(layout func(...rend.Node) rend.Node) sessionOption {
+	return func(s *liveSession) {
+		s.layout = layout
+	}
+}
+
+// LiveSession returns a group adding routes to the live session name.
+// Routes of other sessions are reached with a full page load.
+//
+//	admin := rt.LiveSession("admin",
+//		router.RequireAuth(isAdmin, "/login"),
+//		router.OnMount(loadAdmin),
+//	)
+//	admin.Handle("/admin/users", &UsersLive{})
+func (r *router) LiveSession(name string, opts ...sessionOption) *routeGroup {
+	if _, ok := r.sessions[name]; ok {
+		panic(fmt.Sprintf("router: live session %s already exists", name))
+	}
+
+	s := &liveSession{
+		name: name,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	r.sessions[name] = s
+
+	return &routeGroup{
+		router: r,
+		path:   "/",
+		options: []routeOption{
+			WithSession(name),
+			func(r *route) {
+				r.live = s
+			},
+		},
+	}
+}
+
+func (s *liveSession) middleware() []lv.Middleware {
+	if s == nil {
+		return nil
+	}
+
+	return slices.Concat(s.auth, s.hooks)
+}
@@ -15,7 +15,9 @@ var _ interface {
 	lv.Unmounter
 	lv.Patcher
 	lv.EventHandler
+	lv.AsyncHandler
 	lv.Uploader
+	lv.PageTitler
 } = &wrapper{}
 
 type wrapper struct {
@@ -95,10 +97,48 @@ func (v *wrapper) Params(s lv.Socket, p params.Params) error {
 	return err
 }
 
+// Event sends the event to the handler the innermost route binds with
+// lv.On, parents are only walked while it is not handled. Views
+// implementing Event are called along the way.
 func (v *wrapper) Event(s lv.Socket, e string, p params.Params) error {
-	return walk(v.route, func(route *route) error {
+	var handled, binder, handler bool
+
+	err := walk(v.route, func(route *route) error {
+		if handled {
+			return nil
+		}
+
+		ok, err := lv.TryBoundEvent(route.view, s, e, p)
+		if ok {
+			handled = true
+			return err
+		}
+
+		if _, ok := route.view.(lv.EventBinder); ok {
+			binder = true
+		}
+
+		if _, ok := route.view.(lv.EventHandler); ok {
+			handler = true
+		}
+
 		return lv.TryEvent(route.view, s, e, p)
 	})
+	if err != nil {
+		return err
+	}
+
+	if !handled && binder && !handler {
+		return lv.UnknownEvent(e)
+	}
+
+	return nil
+}
+
+func (v *wrapper) HandleAsync(s lv.Socket, name string, result lv.AsyncResult) error {
+	return walk(v.route, func(route *route) error {
+		return lv.TryHandleAsync(route.view, s, name, result)
+	})
 }
 
 func (v *wrapper) Render(rend.Node) (node rend.Node, err error) {
@@ -106,8 +146,16 @@ func (v *wrapper) Render(rend.Node) (node rend.Node, err error) {
 		node, err = route.view.Render(node)
 		return err
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// app layouts, the first one outermost.
+	for i := len(v.route.layouts) - 1; i >= 0; i-- {
+		node = v.route.layouts[i](node)
+	}
 
-	return node, err
+	return node, nil
 }
 
 func (v *wrapper) Uploads() *uploads.Uploads {
@@ -126,6 +174,20 @@ func (v *wrapper) Uploads() *uploads.Uploads {
 	return u
 }
 
+// PageTitle returns the title of the innermost route that sets one.
+func (v *wrapper) PageTitle() string {
+	var title string
+
+	walk(v.route, func(route *route) error {
+		if title == "" && route.view != nil {
+			title = lv.TryPageTitle(route.view)
+		}
+		return nil
+	})
+
+	return title
+}
+
 func walk(route *route, f func(*route) error) error {
 	for route != nil {
 		err := f(route)
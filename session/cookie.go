@@ -0,0 +1,137 @@
+package session
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	defaultCookieName = "_lv_session"
+	defaultMaxAge     = 14 * 24 * time.Hour
+
+	cookieSalt = "session:cookie"
+	idSalt     = "session:id"
+)
+
+type tokenizer interface {
+	Encode(string, any) (string, error)
+	Decode(string, string, any) error
+}
+
+type CookieOption func(*cookieOptions)
+
+type cookieOptions struct {
+	name     string
+	path     string
+	domain   string
+	maxAge   time.Duration
+	secure   bool
+	sameSite http.SameSite
+}
+
+func newCookieOptions(opts ...CookieOption) *cookieOptions {
+	o := &cookieOptions{
+		name:     defaultCookieName,
+		path:     "/",
+		maxAge:   defaultMaxAge,
+		sameSite: http.SameSiteLaxMode,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func WithCookieName(name string) CookieOption {
+	return func(o *cookieOptions) {
+		o.name = name
+	}
+}
+
+func WithPath(path string) CookieOption {
+	return func(o *cookieOptions) {
+		o.path = path
+	}
+}
+
+func WithDomain(domain string) CookieOption {
+	return func(o *cookieOptions) {
+		o.domain = domain
+	}
+}
+
+func WithMaxAge(d time.Duration) CookieOption {
+	return func(o *cookieOptions) {
+		o.maxAge = d
+	}
+}
+
+func WithSecure(secure bool) CookieOption {
+	return func(o *cookieOptions) {
+		o.secure = secure
+	}
+}
+
+func WithSameSite(sameSite http.SameSite) CookieOption {
+	return func(o *cookieOptions) {
+		o.sameSite = sameSite
+	}
+}
+
+func (o *cookieOptions) cookie(value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     o.name,
+		Value:    value,
+		Path:     o.path,
+		Domain:   o.domain,
+		MaxAge:   int(o.maxAge.Seconds()),
+		Expires:  time.Now().Add(o.maxAge),
+		Secure:   o.secure,
+		HttpOnly: true,
+		SameSite: o.sameSite,
+	}
+}
+
+var _ Store = (*CookieStore)(nil)
+
+// CookieStore keeps all session values in a signed cookie. Values must be
+// JSON serializable and small enough to fit in a cookie.
+type CookieStore struct {
+	tokenizer tokenizer
+	opts      *cookieOptions
+}
+
+func NewCookieStore(t tokenizer, opts ...CookieOption) *CookieStore {
+	return &CookieStore{
+		tokenizer: t,
+		opts:      newCookieOptions(opts...),
+	}
+}
+
+func (c *CookieStore) Load(r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(c.opts.name)
+	if err != nil {
+		return New(c, "", nil), nil
+	}
+
+	values := map[string]any{}
+	err = c.tokenizer.Decode(cookieSalt, cookie.Value, &values)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(c, "", values), nil
+}
+
+func (c *CookieStore) Save(w http.ResponseWriter, s *Session) error {
+	value, err := c.tokenizer.Encode(cookieSalt, s.Values())
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, c.opts.cookie(value))
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+var _ Backend = (*FileBackend)(nil)
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+type fileEntry struct {
+	Values  map[string]any `json:"values"`
+	Expires time.Time      `json:"expires"`
+}
+
+// FileBackend stores every session as a JSON file in a directory.
+type FileBackend struct {
+	dir string
+	now func() time.Time
+}
+
+func NewFileBackend(dir string) (*FileBackend, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileBackend{
+		dir: dir,
+		now: time.Now,
+	}, nil
+}
+
+func (f *FileBackend) Get(id string) (map[string]any, error) {
+	path, err := f.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &fileEntry{}
+	err = json.Unmarshal(data, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.now().After(entry.Expires) {
+		return nil, f.Delete(id)
+	}
+
+	return entry.Values, nil
+}
+
+func (f *FileBackend) Set(id string, values map[string]any, expires time.Time) error {
+	path, err := f.path(id)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&fileEntry{
+		Values:  values,
+		Expires: expires,
+	})
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so readers never see partial data
+	tmp, err := os.CreateTemp(f.dir, ".session-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (f *FileBackend) Delete(id string) error {
+	path, err := f.path(id)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// Sweep removes all expired sessions.
+func (f *FileBackend) Sweep() error {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !validID.MatchString(e.Name()) {
+			continue
+		}
+
+		_, err := f.Get(e.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *FileBackend) path(id string) (string, error) {
+	if !validID.MatchString(id) {
+		return "", fmt.Errorf("invalid session id %q", id)
+	}
+
+	return filepath.Join(f.dir, id), nil
+}
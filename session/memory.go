@@ -0,0 +1,86 @@
+package session
+
+import (
+	"sync"
+	"time"
+)
+
+var _ Backend = (*MemoryBackend)(nil)
+
+type memoryEntry struct {
+	values  map[string]any
+	expires time.Time
+}
+
+// MemoryBackend keeps sessions in process memory, sessions are lost on
+// restart and not shared between nodes.
+type MemoryBackend struct {
+	mu       sync.Mutex
+	sessions map[string]*memoryEntry
+	now      func() time.Time
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		sessions: make(map[string]*memoryEntry),
+		now:      time.Now,
+	}
+}
+
+func (m *MemoryBackend) Get(id string) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+
+	if m.now().After(entry.expires) {
+		delete(m.sessions, id)
+		return nil, nil
+	}
+
+	return copyValues(entry.values), nil
+}
+
+func (m *MemoryBackend) Set(id string, values map[string]any, expires time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep()
+
+	m.sessions[id] = &memoryEntry{
+		values:  copyValues(values),
+		expires: expires,
+	}
+
+	return nil
+}
+
+func (m *MemoryBackend) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, id)
+
+	return nil
+}
+
+// sweep removes expired sessions, it must be called with m.mu held.
+func (m *MemoryBackend) sweep() {
+	now := m.now()
+	for id, entry := range m.sessions {
+		if now.After(entry.expires) {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+func copyValues(values map[string]any) map[string]any {
+	c := make(map[string]any, len(values))
+	for k, v := range values {
+		c[k] = v
+	}
+	return c
+}
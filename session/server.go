@@ -0,0 +1,116 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+)
+
+// Backend persists session values on the server.
+type Backend interface {
+	Get(id string) (map[string]any, error)
+	Set(id string, values map[string]any, expires time.Time) error
+	Delete(id string) error
+}
+
+var _ Store = (*ServerStore)(nil)
+
+// ServerStore keeps session values in a Backend and only the signed
+// session ID in the cookie.
+type ServerStore struct {
+	backend   Backend
+	tokenizer tokenizer
+	opts      *cookieOptions
+}
+
+func NewServerStore(backend Backend, t tokenizer, opts ...CookieOption) *ServerStore {
+	return &ServerStore{
+		backend:   backend,
+		tokenizer: t,
+		opts:      newCookieOptions(opts...),
+	}
+}
+
+func (s *ServerStore) Load(r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(s.opts.name)
+	if err != nil {
+		return s.newSession()
+	}
+
+	var id string
+	err = s.tokenizer.Decode(idSalt, cookie.Value, &id)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := s.backend.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the session expired or was deleted, start over with a new ID
+	if values == nil {
+		return s.newSession()
+	}
+
+	return New(s, id, values), nil
+}
+
+func (s *ServerStore) Save(w http.ResponseWriter, sess *Session) error {
+	if prev := sess.previousID(); prev != "" {
+		err := s.backend.Delete(prev)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := s.backend.Set(sess.ID(), sess.Values(), time.Now().Add(s.opts.maxAge))
+	if err != nil {
+		return err
+	}
+
+	value, err := s.tokenizer.Encode(idSalt, sess.ID())
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, s.opts.cookie(value))
+
+	return nil
+}
+
+// Destroy deletes the session from the backend and expires its cookie.
+func (s *ServerStore) Destroy(w http.ResponseWriter, sess *Session) error {
+	err := s.backend.Delete(sess.ID())
+	if err != nil {
+		return err
+	}
+
+	cookie := s.opts.cookie("")
+	cookie.MaxAge = -1
+	cookie.Expires = time.Unix(0, 0)
+	http.SetCookie(w, cookie)
+
+	return nil
+}
+
+func (s *ServerStore) newSession() (*Session, error) {
+	id, err := NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(s, id, nil), nil
+}
+
+// NewID returns a random session ID.
+func NewID() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,164 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type contextKey struct{}
+
+// Session holds the values of a browser session. Changes are persisted
+// with Save, which must be called before the response is written.
+type Session struct {
+	mu      sync.RWMutex
+	id      string
+	values  map[string]any
+	renewed string
+	store   Store
+}
+
+// Store loads and persists sessions.
+type Store interface {
+	Load(*http.Request) (*Session, error)
+	Save(http.ResponseWriter, *Session) error
+}
+
+// New returns an empty session bound to store.
+func New(store Store, id string, values map[string]any) *Session {
+	if values == nil {
+		values = make(map[string]any)
+	}
+
+	return &Session{
+		id:     id,
+		values: values,
+		store:  store,
+	}
+}
+
+// ID returns the identifier of the session in server side stores.
+func (s *Session) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.id
+}
+
+func (s *Session) Get(key string) any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.values[key]
+}
+
+func (s *Session) Put(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+}
+
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+}
+
+// Clear removes all values from the session.
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values = make(map[string]any)
+}
+
+// Renew gives the session a new ID on the next Save, use it after login
+// to prevent session fixation.
+func (s *Session) Renew(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.renewed == "" {
+		s.renewed = s.id
+	}
+	s.id = id
+}
+
+// Values returns a copy of all session values.
+func (s *Session) Values() map[string]any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	values := make(map[string]any, len(s.values))
+	for k, v := range s.values {
+		values[k] = v
+	}
+
+	return values
+}
+
+// Save persists the session and writes its cookie to w.
+func (s *Session) Save(w http.ResponseWriter) error {
+	return s.store.Save(w, s)
+}
+
+// previousID returns the ID replaced by Renew, if any.
+func (s *Session) previousID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.renewed
+}
+
+// NewContext returns a copy of ctx carrying the session.
+func NewContext(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext returns the session stored in ctx, or nil.
+func FromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(contextKey{}).(*Session)
+	return s
+}
+
+// FromRequest returns the session loaded for r by Middleware or the
+// handler, or nil.
+func FromRequest(r *http.Request) *Session {
+	return FromContext(r.Context())
+}
+
+// Middleware loads the session of every request into its context.
+func Middleware(store Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, Load(store, r))
+		})
+	}
+}
+
+// Load returns r with its session attached. A missing or invalid session
+// is replaced by an empty one.
+func Load(store Store, r *http.Request) *http.Request {
+	if FromRequest(r) != nil {
+		return r
+	}
+
+	s, err := store.Load(r)
+	if err != nil {
+		s = New(store, "", nil)
+	}
+
+	return r.WithContext(NewContext(r.Context(), s))
+}
+
+// Getter exposes the session values of a request, it satisfies the
+// session getter expected by handler.WithSessionGetter.
+type Getter struct {
+	Store Store
+}
+
+func (g *Getter) Get(r *http.Request) map[string]any {
+	return FromRequest(Load(g.Store, r)).Values()
+}
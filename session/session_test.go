@@ -0,0 +1,157 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-live-view/go-live-view/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTokenizer(t *testing.T) tokenizer {
+	tk, err := token.New([]byte(strings.Repeat("s", token.MinKeySize)))
+	assert.NoError(t, err)
+	return tk
+}
+
+// roundTrip saves the session and returns a request carrying its cookie.
+func roundTrip(t *testing.T, s *Session) *http.Request {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	err := s.Save(rec)
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range rec.Result().Cookies() {
+		r.AddCookie(c)
+	}
+
+	return r
+}
+
+func TestCookieStore(t *testing.T) {
+	store := NewCookieStore(newTokenizer(t))
+
+	s, err := store.Load(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{}, s.Values())
+
+	s.Put("user_id", "42")
+	r := roundTrip(t, s)
+
+	s, err = store.Load(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", s.Get("user_id"))
+
+	forged := httptest.NewRequest(http.MethodGet, "/", nil)
+	forged.AddCookie(&http.Cookie{Name: defaultCookieName, Value: "eyJ1c2VyX2lkIjoiMSJ9"})
+
+	_, err = store.Load(forged)
+	assert.Error(t, err)
+}
+
+func TestServerStore(t *testing.T) {
+	backends := map[string]Backend{
+		"memory": NewMemoryBackend(),
+	}
+
+	fb, err := NewFileBackend(t.TempDir())
+	assert.NoError(t, err)
+	backends["file"] = fb
+
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			store := NewServerStore(backend, newTokenizer(t))
+
+			s, err := store.Load(httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.NoError(t, err)
+			assert.NotEmpty(t, s.ID())
+
+			s.Put("user_id", "42")
+			r := roundTrip(t, s)
+
+			loaded, err := store.Load(r)
+			assert.NoError(t, err)
+			assert.Equal(t, s.ID(), loaded.ID())
+			assert.Equal(t, "42", loaded.Get("user_id"))
+
+			oldID := loaded.ID()
+			newID, err := NewID()
+			assert.NoError(t, err)
+			loaded.Renew(newID)
+			r = roundTrip(t, loaded)
+
+			values, err := backend.Get(oldID)
+			assert.NoError(t, err)
+			assert.Nil(t, values)
+
+			renewed, err := store.Load(r)
+			assert.NoError(t, err)
+			assert.Equal(t, newID, renewed.ID())
+			assert.Equal(t, "42", renewed.Get("user_id"))
+
+			rec := httptest.NewRecorder()
+			err = store.Destroy(rec, renewed)
+			assert.NoError(t, err)
+
+			fresh, err := store.Load(r)
+			assert.NoError(t, err)
+			assert.NotEqual(t, newID, fresh.ID())
+			assert.Nil(t, fresh.Get("user_id"))
+		})
+	}
+}
+
+func TestMemoryBackendExpiry(t *testing.T) {
+	m := NewMemoryBackend()
+
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	err := m.Set("a", map[string]any{"k": "v"}, now.Add(time.Minute))
+	assert.NoError(t, err)
+
+	values, err := m.Get("a")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"k": "v"}, values)
+
+	m.now = func() time.Time { return now.Add(2 * time.Minute) }
+
+	values, err = m.Get("a")
+	assert.NoError(t, err)
+	assert.Nil(t, values)
+}
+
+func TestFileBackendInvalidID(t *testing.T) {
+	fb, err := NewFileBackend(t.TempDir())
+	assert.NoError(t, err)
+
+	_, err = fb.Get("../etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	store := NewCookieStore(newTokenizer(t))
+
+	var got *Session
+	h := Middleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromRequest(r)
+		got.Put("seen", true)
+		got.Save(w)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.NotNil(t, got)
+	assert.NotEmpty(t, rec.Result().Cookies())
+
+	getter := &Getter{Store: store}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(rec.Result().Cookies()[0])
+	assert.Equal(t, map[string]any{"seen": true}, getter.Get(r))
+}
@@ -0,0 +1,251 @@
+package token
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// MinKeySize is the minimum length of a secret key in bytes.
+	MinKeySize = 32
+
+	// DefaultMaxAge is how long tokens are valid unless configured otherwise.
+	DefaultMaxAge = 14 * 24 * time.Hour
+
+	signedPrefix    = "S"
+	encryptedPrefix = "E"
+)
+
+var (
+	ErrKeyTooShort = errors.New("token: secret key must be at least 32 bytes")
+	ErrInvalid     = errors.New("token: invalid token")
+	ErrExpired     = errors.New("token: expired token")
+)
+
+type Option func(*Tokenizer)
+
+// Tokenizer signs, and optionally encrypts, values into URL safe tokens.
+// Every token is bound to a salt so a token issued for one purpose cannot
+// be replayed for another.
+type Tokenizer struct {
+	keys    [][]byte
+	encrypt bool
+	maxAge  time.Duration
+	now     func() time.Time
+}
+
+type payload struct {
+	Data     json.RawMessage `json:"d"`
+	IssuedAt int64           `json:"t"`
+}
+
+// New returns a Tokenizer using secret for new tokens.
+func New(secret []byte, opts ...Option) (*Tokenizer, error) {
+	t := &Tokenizer{
+		keys:   [][]byte{secret},
+		maxAge: DefaultMaxAge,
+		now:    time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	for _, key := range t.keys {
+		if len(key) < MinKeySize {
+			return nil, ErrKeyTooShort
+		}
+	}
+
+	return t, nil
+}
+
+// WithRotatedKeys accepts tokens created with previous secrets. New
+// tokens are always created with the primary secret.
+func WithRotatedKeys(keys ...[]byte) Option {
+	return func(t *Tokenizer) {
+		t.keys = append(t.keys, keys...)
+	}
+}
+
+// WithEncryption encrypts tokens with AES-GCM so clients cannot read them.
+func WithEncryption() Option {
+	return func(t *Tokenizer) {
+		t.encrypt = true
+	}
+}
+
+// WithMaxAge sets how long tokens are valid. Zero disables expiry.
+func WithMaxAge(d time.Duration) Option {
+	return func(t *Tokenizer) {
+		t.maxAge = d
+	}
+}
+
+// RandomKey returns a new random secret key.
+func RandomKey() ([]byte, error) {
+	key := make([]byte, MinKeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (t *Tokenizer) Encode(salt string, v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	p, err := json.Marshal(&payload{
+		Data:     data,
+		IssuedAt: t.now().Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if t.encrypt {
+		return seal(t.keys[0], salt, p)
+	}
+
+	return sign(t.keys[0], salt, p), nil
+}
+
+func (t *Tokenizer) Decode(salt string, token string, v any) error {
+	p, err := t.open(salt, token)
+	if err != nil {
+		return err
+	}
+
+	decoded := &payload{}
+	err = json.Unmarshal(p, decoded)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if t.maxAge > 0 {
+		issued := time.Unix(decoded.IssuedAt, 0)
+		if t.now().Sub(issued) > t.maxAge {
+			return ErrExpired
+		}
+	}
+
+	return json.Unmarshal(decoded.Data, v)
+}
+
+// open verifies the token against all keys and returns its payload.
+func (t *Tokenizer) open(salt string, token string) ([]byte, error) {
+	prefix, open := signedPrefix, verify
+	if t.encrypt {
+		prefix, open = encryptedPrefix, unseal
+	}
+
+	if !strings.HasPrefix(token, prefix+".") {
+		return nil, ErrInvalid
+	}
+
+	for _, key := range t.keys {
+		p, err := open(key, salt, token)
+		if err == nil {
+			return p, nil
+		}
+	}
+
+	return nil, ErrInvalid
+}
+
+func sign(key []byte, salt string, p []byte) string {
+	body := signedPrefix + "." + encode(p)
+	return body + "." + encode(mac(derive(key, "sign", salt), body))
+}
+
+func verify(key []byte, salt string, token string) ([]byte, error) {
+	i := strings.LastIndex(token, ".")
+	if i < 0 {
+		return nil, ErrInvalid
+	}
+	body, sig := token[:i], token[i+1:]
+
+	expected, err := decode(sig)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	if !hmac.Equal(expected, mac(derive(key, "sign", salt), body)) {
+		return nil, ErrInvalid
+	}
+
+	return decode(strings.TrimPrefix(body, signedPrefix+"."))
+}
+
+func seal(key []byte, salt string, p []byte) (string, error) {
+	aead, err := newAEAD(key, salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, p, []byte(salt))
+
+	return encryptedPrefix + "." + encode(sealed), nil
+}
+
+func unseal(key []byte, salt string, token string) ([]byte, error) {
+	aead, err := newAEAD(key, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := decode(strings.TrimPrefix(token, encryptedPrefix+"."))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalid
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	return aead.Open(nil, nonce, ciphertext, []byte(salt))
+}
+
+func newAEAD(key []byte, salt string) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(derive(key, "encrypt", salt))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// derive returns a 32 byte key specific to the usage and the salt.
+func derive(key []byte, usage, salt string) []byte {
+	return mac(key, "go-live-view:"+usage+":"+salt)
+}
+
+func mac(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
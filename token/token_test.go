@@ -0,0 +1,117 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	key    = []byte(strings.Repeat("k", MinKeySize))
+	oldKey = []byte(strings.Repeat("o", MinKeySize))
+)
+
+type session struct {
+	UserID string `json:"user_id"`
+}
+
+func TestTokenizer(t *testing.T) {
+	tt := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "signed"},
+		{name: "encrypted", opts: []Option{WithEncryption()}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tk, err := New(key, tc.opts...)
+			assert.NoError(t, err)
+
+			token, err := tk.Encode("session", &session{UserID: "42"})
+			assert.NoError(t, err)
+
+			decoded := &session{}
+			err = tk.Decode("session", token, decoded)
+			assert.NoError(t, err)
+			assert.Equal(t, "42", decoded.UserID)
+
+			err = tk.Decode("static", token, &session{})
+			assert.ErrorIs(t, err, ErrInvalid)
+
+			tampered := token[:len(token)-2] + "AA"
+			err = tk.Decode("session", tampered, &session{})
+			assert.ErrorIs(t, err, ErrInvalid)
+		})
+	}
+}
+
+func TestTokenizerEncryptionHidesPayload(t *testing.T) {
+	signed, err := New(key)
+	assert.NoError(t, err)
+	encrypted, err := New(key, WithEncryption())
+	assert.NoError(t, err)
+
+	token, err := signed.Encode("session", "secret-value")
+	assert.NoError(t, err)
+	payload, err := decode(strings.Split(token, ".")[1])
+	assert.NoError(t, err)
+	assert.Contains(t, string(payload), "secret-value")
+
+	token, err = encrypted.Encode("session", "secret-value")
+	assert.NoError(t, err)
+	assert.NotContains(t, token, "secret-value")
+
+	err = signed.Decode("session", token, new(string))
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestTokenizerKeyRotation(t *testing.T) {
+	old, err := New(oldKey)
+	assert.NoError(t, err)
+
+	rotated, err := New(key, WithRotatedKeys(oldKey))
+	assert.NoError(t, err)
+
+	fresh, err := New(key)
+	assert.NoError(t, err)
+
+	token, err := old.Encode("session", "value")
+	assert.NoError(t, err)
+
+	var v string
+	assert.NoError(t, rotated.Decode("session", token, &v))
+	assert.Equal(t, "value", v)
+
+	assert.ErrorIs(t, fresh.Decode("session", token, &v), ErrInvalid)
+}
+
+func TestTokenizerMaxAge(t *testing.T) {
+	tk, err := New(key, WithMaxAge(time.Hour))
+	assert.NoError(t, err)
+
+	now := time.Now()
+	tk.now = func() time.Time { return now }
+
+	token, err := tk.Encode("session", "value")
+	assert.NoError(t, err)
+
+	var v string
+
+	tk.now = func() time.Time { return now.Add(30 * time.Minute) }
+	assert.NoError(t, tk.Decode("session", token, &v))
+
+	tk.now = func() time.Time { return now.Add(2 * time.Hour) }
+	assert.ErrorIs(t, tk.Decode("session", token, &v), ErrExpired)
+}
+
+func TestNewKeyTooShort(t *testing.T) {
+	_, err := New([]byte("short"))
+	assert.ErrorIs(t, err, ErrKeyTooShort)
+
+	_, err = New(key, WithRotatedKeys([]byte("short")))
+	assert.ErrorIs(t, err, ErrKeyTooShort)
+}
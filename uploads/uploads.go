@@ -191,7 +191,7 @@ func (c *Config) OnAllowUploads(params params.Params) {
 				LastModified: entry.Int("last_modified"),
 				RelativePath: entry.String("relative_path"),
 			},
-			UUID:      c.Ref + "-" + entry.String("ref"), // TODO: encode with proper token
+			UUID:      c.Ref + "-" + entry.String("ref"),
 			Preflight: true,
 		}
 	}